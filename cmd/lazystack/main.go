@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/craigderington/lazystack/internal/config"
+	"github.com/craigderington/lazystack/internal/ui"
+	"k8s.io/klog/v2"
+)
+
+func main() {
+	configPath := flag.String("config", "", "path to config file (default: ~/.config/lazystack/config.yaml)")
+	flag.Parse()
+
+	// client-go logs per-connection port-forward errors to stderr, which
+	// would draw over the TUI; failures that matter are surfaced in the UI
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lazystack: %v\n", err)
+		os.Exit(1)
+	}
+
+	model, err := ui.NewModel(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lazystack: %v\n", err)
+		os.Exit(1)
+	}
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "lazystack: %v\n", err)
+		os.Exit(1)
+	}
+}
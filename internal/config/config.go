@@ -2,16 +2,22 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Systemd    SystemdConfig    `mapstructure:"systemd"`
-	Kubernetes KubernetesConfig `mapstructure:"kubernetes"`
-	UI         UIConfig         `mapstructure:"ui"`
+	Systemd      SystemdConfig      `mapstructure:"systemd"`
+	Kubernetes   KubernetesConfig   `mapstructure:"kubernetes"`
+	UI           UIConfig           `mapstructure:"ui"`
+	PortForwards PortForwardsConfig `mapstructure:"port_forwards"`
+	Logs         LogsConfig         `mapstructure:"logs"`
 }
 
 // SystemdConfig contains systemd-specific configuration
@@ -22,17 +28,67 @@ type SystemdConfig struct {
 
 // KubernetesConfig contains Kubernetes-specific configuration
 type KubernetesConfig struct {
-	Kubeconfig          string `mapstructure:"kubeconfig"`
-	DefaultContext      string `mapstructure:"default_context"`
-	DefaultNamespace    string `mapstructure:"default_namespace"`
-	AutoRefreshInterval string `mapstructure:"auto_refresh_interval"`
+	Kubeconfig          string   `mapstructure:"kubeconfig"`
+	DefaultContext      string   `mapstructure:"default_context"`
+	DefaultNamespace    string   `mapstructure:"default_namespace"` // empty uses the context's namespace
+	AutoRefreshInterval string   `mapstructure:"auto_refresh_interval"`
+	ExecShells          []string `mapstructure:"exec_shells"` // tried in order when opening a shell
 }
 
 // UIConfig contains UI-specific configuration
 type UIConfig struct {
 	Theme      string  `mapstructure:"theme"`
 	VimMode    bool    `mapstructure:"vim_mode"`
-	SplitRatio float64 `mapstructure:"split_ratio"`
+	SplitRatio float64 `mapstructure:"split_ratio"` // fraction of the width used by the left pane
+}
+
+// PortForwardsConfig contains port-forward presets
+type PortForwardsConfig struct {
+	Presets []PortForwardPreset `mapstructure:"presets"`
+}
+
+// PortForwardPreset is a named port forward to whichever pod matches PodSelector
+type PortForwardPreset struct {
+	Name        string `mapstructure:"name"`
+	Namespace   string `mapstructure:"namespace"`    // empty for the current namespace
+	PodSelector string `mapstructure:"pod_selector"` // label selector, e.g. "app=redis"
+	LocalPort   int    `mapstructure:"local_port"`
+	RemotePort  int    `mapstructure:"remote_port"`
+}
+
+// LogsConfig contains log viewer configuration
+type LogsConfig struct {
+	SavedFilters []string `mapstructure:"saved_filters"` // regexes offered at the filter prompt, "!" inverts
+	Fields       []string `mapstructure:"fields"`        // structured log fields shown after the message, empty for all
+	Dir          string   `mapstructure:"dir"`           // where saved and recorded logs are written
+	RecordMaxMB  int      `mapstructure:"record_max_mb"` // size at which a recording is rotated
+	RecordFiles  int      `mapstructure:"record_files"`  // rotated recording files kept
+}
+
+// RecordMaxSize returns the rotation size of log recordings in bytes
+func (c LogsConfig) RecordMaxSize() int64 {
+	return int64(c.RecordMaxMB) * 1024 * 1024
+}
+
+// defaultLogsDir is where saved and recorded logs go unless logs.dir is set
+const defaultLogsDir = "~/lazystack-logs"
+
+// Split ratio bounds; outside these one of the panes becomes unusable
+const (
+	minSplitRatio = 0.1
+	maxSplitRatio = 0.9
+)
+
+// RefreshInterval returns the parsed systemd auto-refresh interval
+func (c SystemdConfig) RefreshInterval() time.Duration {
+	d, _ := time.ParseDuration(c.AutoRefreshInterval)
+	return d
+}
+
+// RefreshInterval returns the parsed Kubernetes auto-refresh interval
+func (c KubernetesConfig) RefreshInterval() time.Duration {
+	d, _ := time.ParseDuration(c.AutoRefreshInterval)
+	return d
 }
 
 // Load loads the configuration from file
@@ -41,11 +97,14 @@ func Load(configPath string) (*Config, error) {
 
 	// Set defaults
 	v.SetDefault("systemd.auto_refresh_interval", "5s")
-	v.SetDefault("kubernetes.default_namespace", "default")
 	v.SetDefault("kubernetes.auto_refresh_interval", "3s")
+	v.SetDefault("kubernetes.exec_shells", []string{"bash", "sh"})
 	v.SetDefault("ui.theme", "default")
 	v.SetDefault("ui.vim_mode", true)
-	v.SetDefault("ui.split_ratio", 0.5)
+	v.SetDefault("ui.split_ratio", 0.33)
+	v.SetDefault("logs.dir", defaultLogsDir)
+	v.SetDefault("logs.record_max_mb", 10)
+	v.SetDefault("logs.record_files", 5)
 
 	// Set config file path if provided
 	if configPath != "" {
@@ -72,30 +131,141 @@ func Load(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
-	// Set default kubeconfig path if not specified
-	if config.Kubernetes.Kubeconfig == "" {
-		home := viper.GetString("HOME")
-		config.Kubernetes.Kubeconfig = filepath.Join(home, ".kube", "config")
+	// An empty kubeconfig is left for the k8s manager to resolve from
+	// $KUBECONFIG or ~/.kube/config
+	kubeconfig, err := expandHome(config.Kubernetes.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubernetes.kubeconfig: %w", err)
+	}
+	config.Kubernetes.Kubeconfig = kubeconfig
+
+	logsDir, err := expandHome(config.Logs.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logs.dir: %w", err)
+	}
+	config.Logs.Dir = logsDir
+
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 
 	return &config, nil
 }
 
+// Validate checks that durations and ratios hold usable values
+func (c *Config) Validate() error {
+	if err := validateInterval("systemd.auto_refresh_interval", c.Systemd.AutoRefreshInterval); err != nil {
+		return err
+	}
+	if err := validateInterval("kubernetes.auto_refresh_interval", c.Kubernetes.AutoRefreshInterval); err != nil {
+		return err
+	}
+	if len(c.Kubernetes.ExecShells) == 0 {
+		return fmt.Errorf("invalid kubernetes.exec_shells: at least one shell is required")
+	}
+	if c.UI.SplitRatio < minSplitRatio || c.UI.SplitRatio > maxSplitRatio {
+		return fmt.Errorf("invalid ui.split_ratio %g: must be between %g and %g",
+			c.UI.SplitRatio, minSplitRatio, maxSplitRatio)
+	}
+	if err := c.PortForwards.validate(); err != nil {
+		return err
+	}
+	return c.Logs.validate()
+}
+
+// validate checks that every saved filter is a valid regex and that
+// recordings can be written and rotated
+func (c LogsConfig) validate() error {
+	if c.Dir == "" {
+		return fmt.Errorf("invalid logs.dir: a directory is required")
+	}
+	if c.RecordMaxMB <= 0 {
+		return fmt.Errorf("invalid logs.record_max_mb %d: must be greater than zero", c.RecordMaxMB)
+	}
+	if c.RecordFiles <= 0 {
+		return fmt.Errorf("invalid logs.record_files %d: must be greater than zero", c.RecordFiles)
+	}
+	for i, f := range c.SavedFilters {
+		if _, err := regexp.Compile(strings.TrimPrefix(f, "!")); err != nil {
+			return fmt.Errorf("invalid logs.saved_filters[%d] %q: %w", i, f, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that every preset is named uniquely and has usable ports
+func (c PortForwardsConfig) validate() error {
+	seen := make(map[string]bool, len(c.Presets))
+	for i, p := range c.Presets {
+		if p.Name == "" {
+			return fmt.Errorf("invalid port_forwards.presets[%d]: name is required", i)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("invalid port_forwards.presets[%d]: duplicate name %q", i, p.Name)
+		}
+		seen[p.Name] = true
+
+		if p.PodSelector == "" {
+			return fmt.Errorf("invalid port-forward preset %q: pod_selector is required", p.Name)
+		}
+		if !validPort(p.LocalPort) || !validPort(p.RemotePort) {
+			return fmt.Errorf("invalid port-forward preset %q: ports must be between 1 and 65535", p.Name)
+		}
+	}
+	return nil
+}
+
+// validPort reports whether port is a usable TCP port number
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+// validateInterval checks that value is a positive Go duration such as "5s"
+func validateInterval(key, value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("invalid %s %q: must be greater than zero", key, value)
+	}
+	return nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to expand %s: %w", path, err)
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
 // GetDefaultConfig returns a configuration with default values
 func GetDefaultConfig() *Config {
+	logsDir, _ := expandHome(defaultLogsDir)
 	return &Config{
 		Systemd: SystemdConfig{
 			UnitsToWatch:        []string{},
 			AutoRefreshInterval: "5s",
 		},
 		Kubernetes: KubernetesConfig{
-			DefaultNamespace:    "default",
 			AutoRefreshInterval: "3s",
+			ExecShells:          []string{"bash", "sh"},
 		},
 		UI: UIConfig{
 			Theme:      "default",
 			VimMode:    true,
-			SplitRatio: 0.5,
+			SplitRatio: 0.33,
+		},
+		Logs: LogsConfig{
+			Dir:         logsDir,
+			RecordMaxMB: 10,
+			RecordFiles: 5,
 		},
 	}
 }
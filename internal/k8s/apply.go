@@ -0,0 +1,188 @@
+package k8s
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// fieldManager is the field manager lazystack applies changes as
+const fieldManager = "lazystack"
+
+// resourceKinds maps the resource types lazystack shows to their kinds
+var resourceKinds = map[string]schema.GroupVersionKind{
+	"pod":        {Version: "v1", Kind: "Pod"},
+	"deployment": {Group: "apps", Version: "v1", Kind: "Deployment"},
+	"service":    {Version: "v1", Kind: "Service"},
+}
+
+// serverSetFields are set by the server and left out of YAML for editing.
+// resourceVersion stays, so applying fails if the object changed meanwhile.
+var serverSetFields = [][]string{
+	{"metadata", "managedFields"},
+	{"metadata", "uid"},
+	{"metadata", "creationTimestamp"},
+	{"metadata", "generation"},
+	{"metadata", "selfLink"},
+	{"status"},
+}
+
+// ApplyResult is the live object next to what an apply makes of it, both
+// as YAML without server-set fields
+type ApplyResult struct {
+	Live   string
+	Result string
+}
+
+// getResourceYAML returns a resource as YAML the way kubectl shows it, with
+// apiVersion and kind set and without managedFields
+func (m *Manager) getResourceYAML(resourceType, name string) (string, error) {
+	clientset, namespace := m.client()
+	obj, err := getObject(clientset, namespace, resourceType, name)
+	if err != nil {
+		return "", err
+	}
+	return objectYAML(obj, resourceKinds[resourceType], [][]string{{"metadata", "managedFields"}})
+}
+
+// GetEditableYAML returns a resource as YAML for editing, with apiVersion
+// and kind set and the fields the server sets left out
+func (m *Manager) GetEditableYAML(resourceType, name string) (string, error) {
+	clientset, namespace := m.client()
+	obj, err := getObject(clientset, namespace, resourceType, name)
+	if err != nil {
+		return "", err
+	}
+	return objectYAML(obj, resourceKinds[resourceType], serverSetFields)
+}
+
+// ApplyYAML server-side applies edited YAML to a resource. With dryRun the
+// server validates the change and returns the result without persisting
+// it. Changing fields another field manager owns fails unless force is set.
+func (m *Manager) ApplyYAML(resourceType, name, content string, dryRun, force bool) (*ApplyResult, error) {
+	gvk, ok := resourceKinds[resourceType]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type: %s", resourceType)
+	}
+
+	data, err := yaml.YAMLToJSON([]byte(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	var edited unstructured.Unstructured
+	if err := edited.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if edited.GroupVersionKind() != gvk || edited.GetName() != name {
+		return nil, fmt.Errorf("the YAML must describe %s %s", gvk.Kind, name)
+	}
+	clientset, namespace := m.client()
+	if ns := edited.GetNamespace(); ns != "" && ns != namespace {
+		return nil, fmt.Errorf("the YAML must stay in namespace %s", namespace)
+	}
+
+	live, err := getObject(clientset, namespace, resourceType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := metav1.PatchOptions{FieldManager: fieldManager, Force: &force}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	ctx := context.Background()
+	var applied runtime.Object
+	switch resourceType {
+	case "pod":
+		applied, err = clientset.CoreV1().Pods(namespace).Patch(ctx, name, types.ApplyPatchType, data, opts)
+	case "deployment":
+		applied, err = clientset.AppsV1().Deployments(namespace).Patch(ctx, name, types.ApplyPatchType, data, opts)
+	case "service":
+		applied, err = clientset.CoreV1().Services(namespace).Patch(ctx, name, types.ApplyPatchType, data, opts)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply %s %s: %w", resourceType, name, err)
+	}
+
+	// resourceVersion changes on every write, so it's left out of the comparison
+	result := &ApplyResult{}
+	compared := append(slices.Clone(serverSetFields), []string{"metadata", "resourceVersion"})
+	if result.Live, err = objectYAML(live, gvk, compared); err != nil {
+		return nil, err
+	}
+	if result.Result, err = objectYAML(applied, gvk, compared); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// IsFieldManagerConflict reports whether an apply failed because it changes
+// fields another field manager owns, which forcing the apply overrides
+func IsFieldManagerConflict(err error) bool {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || !apierrors.IsConflict(err) {
+		return false
+	}
+	if details := status.Status().Details; details != nil {
+		for _, cause := range details.Causes {
+			if cause.Type == metav1.CauseTypeFieldManagerConflict {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getObject gets a pod, deployment or service by name
+func getObject(clientset *kubernetes.Clientset, namespace, resourceType, name string) (runtime.Object, error) {
+	ctx := context.Background()
+	var (
+		obj runtime.Object
+		err error
+	)
+	switch resourceType {
+	case "pod":
+		obj, err = clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "deployment":
+		obj, err = clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	case "service":
+		obj, err = clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		return nil, fmt.Errorf("unknown resource type: %s", resourceType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", resourceType, name, err)
+	}
+	return obj, nil
+}
+
+// objectYAML marshals an object with apiVersion and kind set, which typed
+// clients leave empty, and the given fields left out
+func objectYAML(obj runtime.Object, gvk schema.GroupVersionKind, omit [][]string) (string, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert %s: %w", gvk.Kind, err)
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	for _, field := range omit {
+		unstructured.RemoveNestedField(u.Object, field...)
+	}
+
+	out, err := yaml.Marshal(u.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s to YAML: %w", gvk.Kind, err)
+	}
+	return string(out), nil
+}
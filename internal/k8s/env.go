@@ -0,0 +1,324 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// envSources fetches the ConfigMaps and Secrets a pod's environment refers
+// to, each at most once
+type envSources struct {
+	clientset  *kubernetes.Clientset
+	namespace  string
+	configMaps map[string]*corev1.ConfigMap
+	secrets    map[string]*corev1.Secret
+	errs       map[string]error
+}
+
+func newEnvSources(clientset *kubernetes.Clientset, namespace string) *envSources {
+	return &envSources{
+		clientset:  clientset,
+		namespace:  namespace,
+		configMaps: make(map[string]*corev1.ConfigMap),
+		secrets:    make(map[string]*corev1.Secret),
+		errs:       make(map[string]error),
+	}
+}
+
+// configMap returns a ConfigMap's data, binary keys included
+func (s *envSources) configMap(name string) (map[string]string, error) {
+	cm, ok := s.configMaps[name]
+	if !ok {
+		if err, failed := s.errs["configmap/"+name]; failed {
+			return nil, err
+		}
+		var err error
+		cm, err = s.clientset.CoreV1().ConfigMaps(s.namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			err = fmt.Errorf("failed to get ConfigMap %s: %w", name, err)
+			s.errs["configmap/"+name] = err
+			return nil, err
+		}
+		s.configMaps[name] = cm
+	}
+
+	data := make(map[string]string, len(cm.Data)+len(cm.BinaryData))
+	for k, v := range cm.Data {
+		data[k] = v
+	}
+	for k, v := range cm.BinaryData {
+		data[k] = string(v)
+	}
+	return data, nil
+}
+
+// secret returns a Secret's decoded data
+func (s *envSources) secret(name string) (map[string]string, error) {
+	secret, ok := s.secrets[name]
+	if !ok {
+		if err, failed := s.errs["secret/"+name]; failed {
+			return nil, err
+		}
+		var err error
+		secret, err = s.clientset.CoreV1().Secrets(s.namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			err = fmt.Errorf("failed to get Secret %s: %w", name, err)
+			s.errs["secret/"+name] = err
+			return nil, err
+		}
+		s.secrets[name] = secret
+	}
+
+	data := make(map[string]string, len(secret.Data))
+	for k, v := range secret.Data {
+		data[k] = string(v)
+	}
+	return data, nil
+}
+
+// containerEnv resolves a container's environment the way the kubelet
+// builds it: envFrom sources first, then env, later definitions replacing
+// earlier ones of the same name
+func containerEnv(pod *corev1.Pod, container corev1.Container, sources *envSources) []EnvVar {
+	var vars []EnvVar
+	index := make(map[string]int)
+	set := func(v EnvVar) {
+		if i, ok := index[v.Name]; ok {
+			vars[i] = v
+			return
+		}
+		index[v.Name] = len(vars)
+		vars = append(vars, v)
+	}
+	values := func() map[string]string {
+		defined := make(map[string]string, len(vars))
+		for _, v := range vars {
+			if v.Error == "" {
+				defined[v.Name] = v.Value
+			}
+		}
+		return defined
+	}
+
+	for _, envFrom := range container.EnvFrom {
+		var (
+			kind, name string
+			optional   *bool
+			data       map[string]string
+			err        error
+		)
+		switch {
+		case envFrom.ConfigMapRef != nil:
+			kind, name, optional = "ConfigMap", envFrom.ConfigMapRef.Name, envFrom.ConfigMapRef.Optional
+			data, err = sources.configMap(name)
+		case envFrom.SecretRef != nil:
+			kind, name, optional = "Secret", envFrom.SecretRef.Name, envFrom.SecretRef.Optional
+			data, err = sources.secret(name)
+		default:
+			continue
+		}
+		if err != nil {
+			set(EnvVar{
+				Name:      fmt.Sprintf("(all keys from %s %s)", kind, name),
+				ValueFrom: fmt.Sprintf("%s: %s", kind, name),
+				Error:     refError(err, optional),
+			})
+			continue
+		}
+
+		keys := make([]string, 0, len(data))
+		for k := range data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			set(EnvVar{
+				Name:      envFrom.Prefix + k,
+				Value:     data[k],
+				ValueFrom: fmt.Sprintf("%s: %s (key: %s)", kind, name, k),
+				Secret:    kind == "Secret",
+			})
+		}
+	}
+
+	for _, env := range container.Env {
+		v := EnvVar{Name: env.Name}
+		switch from := env.ValueFrom; {
+		case from == nil:
+			v.Value = expandEnvRefs(env.Value, values())
+		case from.ConfigMapKeyRef != nil:
+			ref := from.ConfigMapKeyRef
+			v.ValueFrom = fmt.Sprintf("ConfigMap: %s (key: %s)", ref.Name, ref.Key)
+			v.Value, v.Error = lookupKey(sources.configMap, ref.Name, ref.Key, ref.Optional)
+		case from.SecretKeyRef != nil:
+			ref := from.SecretKeyRef
+			v.ValueFrom = fmt.Sprintf("Secret: %s (key: %s)", ref.Name, ref.Key)
+			v.Value, v.Error = lookupKey(sources.secret, ref.Name, ref.Key, ref.Optional)
+			v.Secret = true
+		case from.FieldRef != nil:
+			v.ValueFrom = fmt.Sprintf("FieldRef: %s", from.FieldRef.FieldPath)
+			value, ok := podFieldValue(pod, from.FieldRef.FieldPath)
+			if ok {
+				v.Value = value
+			} else {
+				v.Error = "field not supported"
+			}
+		case from.ResourceFieldRef != nil:
+			v.ValueFrom = fmt.Sprintf("ResourceFieldRef: %s", from.ResourceFieldRef.Resource)
+			v.Value, v.Error = resourceFieldValue(pod, container, from.ResourceFieldRef)
+		}
+		set(v)
+	}
+
+	return vars
+}
+
+// lookupKey resolves one key of a ConfigMap or Secret, returning why it
+// couldn't be resolved instead of the value on failure
+func lookupKey(get func(string) (map[string]string, error), name, key string, optional *bool) (string, string) {
+	data, err := get(name)
+	if err != nil {
+		return "", refError(err, optional)
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", refError(fmt.Errorf("key %s not found in %s", key, name), optional)
+	}
+	return value, ""
+}
+
+// refError describes a reference that couldn't be resolved. An optional
+// reference leaves the variable unset rather than failing the container.
+func refError(err error, optional *bool) string {
+	if optional != nil && *optional {
+		return fmt.Sprintf("not set, optional: %v", err)
+	}
+	return err.Error()
+}
+
+// podFieldValue resolves the downward API fields env vars can refer to
+func podFieldValue(pod *corev1.Pod, path string) (string, bool) {
+	switch path {
+	case "metadata.name":
+		return pod.Name, true
+	case "metadata.namespace":
+		return pod.Namespace, true
+	case "metadata.uid":
+		return string(pod.UID), true
+	case "spec.nodeName":
+		return pod.Spec.NodeName, true
+	case "spec.serviceAccountName":
+		return pod.Spec.ServiceAccountName, true
+	case "status.hostIP":
+		return pod.Status.HostIP, true
+	case "status.podIP":
+		return pod.Status.PodIP, true
+	case "status.hostIPs":
+		ips := make([]string, 0, len(pod.Status.HostIPs))
+		for _, ip := range pod.Status.HostIPs {
+			ips = append(ips, ip.IP)
+		}
+		return strings.Join(ips, ","), true
+	case "status.podIPs":
+		ips := make([]string, 0, len(pod.Status.PodIPs))
+		for _, ip := range pod.Status.PodIPs {
+			ips = append(ips, ip.IP)
+		}
+		return strings.Join(ips, ","), true
+	}
+
+	// metadata.labels['key'] and metadata.annotations['key']
+	for prefix, values := range map[string]map[string]string{
+		"metadata.labels":      pod.Labels,
+		"metadata.annotations": pod.Annotations,
+	} {
+		if rest, ok := strings.CutPrefix(path, prefix+"['"); ok {
+			if key, ok := strings.CutSuffix(rest, "']"); ok {
+				return values[key], true
+			}
+		}
+	}
+	return "", false
+}
+
+// resourceFieldValue resolves a container's request or limit in units of the
+// reference's divisor, rounding up like the kubelet
+func resourceFieldValue(pod *corev1.Pod, container corev1.Container, ref *corev1.ResourceFieldSelector) (string, string) {
+	if ref.ContainerName != "" && ref.ContainerName != container.Name {
+		i := slices.IndexFunc(pod.Spec.Containers, func(c corev1.Container) bool { return c.Name == ref.ContainerName })
+		if i < 0 {
+			return "", fmt.Sprintf("container %s not found", ref.ContainerName)
+		}
+		container = pod.Spec.Containers[i]
+	}
+
+	kind, resource, _ := strings.Cut(ref.Resource, ".")
+	values := container.Resources.Limits
+	if kind == "requests" {
+		values = container.Resources.Requests
+	}
+	q, ok := values[corev1.ResourceName(resource)]
+	if !ok {
+		if kind == "limits" {
+			return "", "no limit set, the kubelet uses the node's allocatable capacity"
+		}
+		return "0", ""
+	}
+
+	value, divisor := q.Value(), ref.Divisor.Value()
+	if resource == string(corev1.ResourceCPU) {
+		value, divisor = q.MilliValue(), ref.Divisor.MilliValue()
+	}
+	if divisor <= 0 {
+		divisor = 1
+		if resource == string(corev1.ResourceCPU) {
+			divisor = 1000
+		}
+	}
+	return strconv.FormatInt((value+divisor-1)/divisor, 10), ""
+}
+
+// expandEnvRefs expands $(VAR) references to variables defined earlier, as
+// the kubelet does. $$ escapes a $ and unknown references are kept as is.
+func expandEnvRefs(value string, defined map[string]string) string {
+	if !strings.Contains(value, "$") {
+		return value
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '$' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		switch value[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '(':
+			end := strings.IndexByte(value[i+2:], ')')
+			if end < 0 {
+				b.WriteString(value[i:])
+				return b.String()
+			}
+			ref := value[i : i+3+end]
+			if v, ok := defined[ref[2:len(ref)-1]]; ok {
+				b.WriteString(v)
+			} else {
+				b.WriteString(ref)
+			}
+			i += 2 + end
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String()
+}
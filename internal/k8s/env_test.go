@@ -0,0 +1,41 @@
+package k8s
+
+import "testing"
+
+func TestExpandEnvRefs(t *testing.T) {
+	defined := map[string]string{
+		"HOST":   "db.local",
+		"PORT":   "5432",
+		"DOLLAR": "$(HOST)",
+		"EMPTY":  "",
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "no references", value: "plain value", want: "plain value"},
+		{name: "one reference", value: "$(HOST)", want: "db.local"},
+		{name: "several references", value: "postgres://$(HOST):$(PORT)/app", want: "postgres://db.local:5432/app"},
+		{name: "empty value", value: "[$(EMPTY)]", want: "[]"},
+		{name: "values aren't expanded again", value: "$(DOLLAR)", want: "$(HOST)"},
+		{name: "unknown reference kept", value: "$(MISSING)-$(PORT)", want: "$(MISSING)-5432"},
+		{name: "escaped dollar", value: "cost: $$5", want: "cost: $5"},
+		{name: "escaped reference", value: "$$(HOST)", want: "$(HOST)"},
+		{name: "escape before a reference", value: "$$$(HOST)", want: "$db.local"},
+		{name: "double escape", value: "$$$$", want: "$$"},
+		{name: "unterminated reference", value: "$(HOST", want: "$(HOST"},
+		{name: "unterminated after a reference", value: "$(PORT) $(HOST", want: "5432 $(HOST"},
+		{name: "empty reference", value: "$()", want: "$()"},
+		{name: "lone dollar", value: "$HOST and $", want: "$HOST and $"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expandEnvRefs(tt.value, defined); got != tt.want {
+				t.Errorf("expandEnvRefs(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/muesli/cancelreader"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// detachKey ends an exec session from the client side (ctrl+])
+const detachKey = 0x1d
+
+// ExecSession is an interactive shell in a container. It satisfies
+// bubbletea's ExecCommand, so the TUI can hand the terminal over to it.
+type ExecSession struct {
+	PodName   string
+	Namespace string
+	Container string
+	Shells    []string // tried in order until one exists in the container
+
+	conn   connection
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// NewExecSession prepares a shell session in a container of a pod in the
+// current namespace. Nothing is started until Run is called.
+func (m *Manager) NewExecSession(podName, container string, shells []string) *ExecSession {
+	conn := m.current()
+	return &ExecSession{
+		PodName:   podName,
+		Namespace: conn.namespace,
+		Container: container,
+		Shells:    shells,
+		conn:      conn,
+		stdin:     os.Stdin,
+		stdout:    os.Stdout,
+		stderr:    os.Stderr,
+	}
+}
+
+// SetStdin sets the session's input
+func (s *ExecSession) SetStdin(r io.Reader) { s.stdin = r }
+
+// SetStdout sets the session's output
+func (s *ExecSession) SetStdout(w io.Writer) { s.stdout = w }
+
+// SetStderr sets where connection errors are reported
+func (s *ExecSession) SetStderr(w io.Writer) { s.stderr = w }
+
+// Run opens a TTY shell in the container and blocks until the shell exits
+// or the user detaches with ctrl+]
+func (s *ExecSession) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Put the local terminal in raw mode so keystrokes go straight to the shell
+	var tty *os.File
+	if f, ok := s.stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		state, err := term.MakeRaw(int(f.Fd()))
+		if err != nil {
+			return fmt.Errorf("failed to put terminal in raw mode: %w", err)
+		}
+		defer term.Restore(int(f.Fd()), state)
+		tty = f
+	}
+
+	// A cancelable reader lets us stop reading stdin once the session ends,
+	// so the TUI gets every keystroke back
+	input, err := cancelreader.NewReader(s.stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read terminal input: %w", err)
+	}
+	defer input.Cancel()
+	stdin := &detachReader{r: input, detach: cancel}
+
+	fmt.Fprintf(s.stdout, "Connecting to %s/%s (container %s), press ctrl+] to detach\r\n",
+		s.Namespace, s.PodName, s.Container)
+
+	for _, shell := range s.Shells {
+		executor, err := newExecutor(s.conn, s.PodName, s.Container, []string{shell})
+		if err != nil {
+			return err
+		}
+
+		// Each attempt gets its own queue so it starts with the current size
+		var sizeQueue remotecommand.TerminalSizeQueue
+		if tty != nil {
+			sizeQueue = newTerminalSizeQueue(ctx, tty)
+		}
+
+		stdin.typed.Store(false)
+		stdout := &startupWriter{w: s.stdout}
+		err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+			Stdin:             stdin,
+			Stdout:            stdout,
+			Tty:               true,
+			TerminalSizeQueue: sizeQueue,
+		})
+		switch {
+		case err == nil, ctx.Err() != nil:
+			// The shell exited or the user detached
+			return nil
+		case !stdin.typed.Load() && isShellMissing(err, stdout.head.String()):
+			continue
+		default:
+			return fmt.Errorf("exec into %s failed: %w", s.PodName, err)
+		}
+	}
+
+	return fmt.Errorf("no shell found in %s (tried %s)", s.PodName, strings.Join(s.Shells, ", "))
+}
+
+// newExecutor builds an executor that prefers WebSockets and falls back to
+// SPDY for API servers that don't support them yet
+func newExecutor(conn connection, podName, container string, command []string) (remotecommand.Executor, error) {
+	req := conn.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(conn.namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdin:     true,
+			Stdout:    true,
+			TTY:       true,
+		}, scheme.ParameterCodec)
+
+	spdyExec, err := remotecommand.NewSPDYExecutor(conn.restConfig, "POST", req.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SPDY executor: %w", err)
+	}
+	wsExec, err := remotecommand.NewWebSocketExecutor(conn.restConfig, "GET", req.URL().String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create WebSocket executor: %w", err)
+	}
+
+	return remotecommand.NewFallbackExecutor(wsExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+}
+
+// isShellMissing reports whether an exec failed because the container
+// runtime couldn't find the shell binary, rather than the shell itself
+// failing. With a TTY the runtime reports this on the terminal and the exec
+// ends with status 126 or 127, so output is the start of what was printed.
+func isShellMissing(err error, output string) bool {
+	if runtimeNotFound(err.Error()) {
+		return true
+	}
+	var exitErr utilexec.ExitError
+	if !errors.As(err, &exitErr) || (exitErr.ExitStatus() != 126 && exitErr.ExitStatus() != 127) {
+		return false
+	}
+	return runtimeNotFound(output)
+}
+
+// runtimeNotFound reports whether text holds the container runtime's error
+// for a command that doesn't exist
+func runtimeNotFound(text string) bool {
+	return strings.Contains(text, "executable file not found") || strings.Contains(text, "no such file or directory")
+}
+
+// startupHeadSize bounds how much of a session's output startupWriter keeps
+const startupHeadSize = 1024
+
+// startupWriter passes output through, keeping the start of it so a
+// runtime error printed on the terminal can be recognized
+type startupWriter struct {
+	w    io.Writer
+	head bytes.Buffer
+}
+
+func (s *startupWriter) Write(p []byte) (int, error) {
+	if room := startupHeadSize - s.head.Len(); room > 0 {
+		s.head.Write(p[:min(room, len(p))])
+	}
+	return s.w.Write(p)
+}
+
+// detachReader passes input through until it sees the detach key. typed
+// records that input reached the session, which means the shell is running.
+type detachReader struct {
+	r      io.Reader
+	detach context.CancelFunc
+	typed  atomic.Bool
+}
+
+func (d *detachReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	for i := 0; i < n; i++ {
+		if p[i] == detachKey {
+			d.detach()
+			if i > 0 {
+				d.typed.Store(true)
+			}
+			return i, io.EOF
+		}
+	}
+	if n > 0 {
+		d.typed.Store(true)
+	}
+	return n, err
+}
+
+// terminalSizeQueue reports the local terminal size on start and on every SIGWINCH
+type terminalSizeQueue struct {
+	sizes chan remotecommand.TerminalSize
+}
+
+func newTerminalSizeQueue(ctx context.Context, f *os.File) *terminalSizeQueue {
+	q := &terminalSizeQueue{sizes: make(chan remotecommand.TerminalSize, 1)}
+
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+
+	go func() {
+		defer signal.Stop(winch)
+		defer close(q.sizes)
+
+		for {
+			if width, height, err := term.GetSize(int(f.Fd())); err == nil {
+				select {
+				case q.sizes <- remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}:
+				default:
+					// A resize is already pending; the next SIGWINCH catches up
+				}
+			}
+			select {
+			case <-winch:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return q
+}
+
+// Next blocks until the terminal is resized; nil ends resize handling
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q.sizes
+	if !ok {
+		return nil
+	}
+	return &size
+}
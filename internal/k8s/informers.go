@@ -0,0 +1,224 @@
+package k8s
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// eventBuffer sizes the channel carrying informer events to the UI
+const eventBuffer = 1024
+
+// informerResync is how often informers replay their cache as updates
+const informerResync = 10 * time.Minute
+
+// EventType describes what happened to a watched resource
+type EventType int
+
+const (
+	ResourceAdded EventType = iota
+	ResourceUpdated
+	ResourceDeleted
+	// ResourcesReset is sent when informers restart, e.g. after a namespace
+	// switch; consumers should drop everything they have cached
+	ResourcesReset
+)
+
+// ResourceEvent is an incremental change reported by the informers.
+// Exactly one of Pod, Deployment or Service is set, except for ResourcesReset.
+// Generation grows each time informers restart; an event from an older
+// generation than the last ResourcesReset is stale and should be dropped.
+type ResourceEvent struct {
+	Type       EventType
+	Namespace  string
+	Generation uint64
+	Pod        *PodInfo
+	Deployment *DeploymentInfo
+	Service    *ServiceInfo
+}
+
+// Events returns the channel informer events are delivered on. It stays the
+// same across namespace switches, so it only needs to be read from once.
+func (m *Manager) Events() <-chan ResourceEvent {
+	return m.events
+}
+
+// StartInformers starts pod, deployment and service informers for the
+// current namespace, replacing any informers that are already running
+func (m *Manager) StartInformers() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.startInformersLocked()
+}
+
+// RestartInformers restarts the running informers, which re-lists every
+// resource. It reports false and does nothing when no informers are running.
+func (m *Manager) RestartInformers() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.factory == nil {
+		return false
+	}
+	m.startInformersLocked()
+	return true
+}
+
+// StopInformers stops all running informers
+func (m *Manager) StopInformers() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stopInformersLocked()
+}
+
+// startInformersLocked (re)starts informers scoped to the connection's namespace. m.mu must be held.
+func (m *Manager) startInformersLocked() {
+	m.stopInformersLocked()
+
+	m.generation++
+	namespace, gen := m.conn.namespace, m.generation
+	stopCh := make(chan struct{})
+	m.resetEvents(namespace, gen)
+
+	factory := informers.NewSharedInformerFactoryWithOptions(m.conn.clientset, informerResync,
+		informers.WithNamespace(namespace))
+
+	factory.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				info := toPodInfo(pod)
+				m.send(ResourceEvent{Type: ResourceAdded, Namespace: namespace, Generation: gen, Pod: &info}, stopCh)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				info := toPodInfo(pod)
+				m.send(ResourceEvent{Type: ResourceUpdated, Namespace: namespace, Generation: gen, Pod: &info}, stopCh)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if pod, ok := deletedObject(obj).(*corev1.Pod); ok {
+				info := toPodInfo(pod)
+				m.send(ResourceEvent{Type: ResourceDeleted, Namespace: namespace, Generation: gen, Pod: &info}, stopCh)
+			}
+		},
+	})
+
+	factory.Apps().V1().Deployments().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if deploy, ok := obj.(*appsv1.Deployment); ok {
+				info := toDeploymentInfo(deploy)
+				m.send(ResourceEvent{Type: ResourceAdded, Namespace: namespace, Generation: gen, Deployment: &info}, stopCh)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if deploy, ok := obj.(*appsv1.Deployment); ok {
+				info := toDeploymentInfo(deploy)
+				m.send(ResourceEvent{Type: ResourceUpdated, Namespace: namespace, Generation: gen, Deployment: &info}, stopCh)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if deploy, ok := deletedObject(obj).(*appsv1.Deployment); ok {
+				info := toDeploymentInfo(deploy)
+				m.send(ResourceEvent{Type: ResourceDeleted, Namespace: namespace, Generation: gen, Deployment: &info}, stopCh)
+			}
+		},
+	})
+
+	factory.Core().V1().Services().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if svc, ok := obj.(*corev1.Service); ok {
+				info := toServiceInfo(svc)
+				m.send(ResourceEvent{Type: ResourceAdded, Namespace: namespace, Generation: gen, Service: &info}, stopCh)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if svc, ok := obj.(*corev1.Service); ok {
+				info := toServiceInfo(svc)
+				m.send(ResourceEvent{Type: ResourceUpdated, Namespace: namespace, Generation: gen, Service: &info}, stopCh)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if svc, ok := deletedObject(obj).(*corev1.Service); ok {
+				info := toServiceInfo(svc)
+				m.send(ResourceEvent{Type: ResourceDeleted, Namespace: namespace, Generation: gen, Service: &info}, stopCh)
+			}
+		},
+	})
+
+	m.factory = factory
+	m.stopCh = stopCh
+	factory.Start(stopCh)
+}
+
+// podCache returns a connection snapshot and the pod lister of the informers
+// running for it. The lister is nil until the pod informer has synced, or
+// when no informers are running.
+func (m *Manager) podCache() (connection, corelisters.PodLister) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.factory == nil {
+		return m.conn, nil
+	}
+	pods := m.factory.Core().V1().Pods()
+	if !pods.Informer().HasSynced() {
+		return m.conn, nil
+	}
+	return m.conn, pods.Lister()
+}
+
+// stopInformersLocked stops the running informer factory, if any. m.mu must be held.
+func (m *Manager) stopInformersLocked() {
+	if m.factory == nil {
+		return
+	}
+
+	close(m.stopCh)
+	// Shutdown waits for informer goroutines to exit; don't hold up the UI on it
+	go m.factory.Shutdown()
+
+	m.factory = nil
+	m.stopCh = nil
+}
+
+// send delivers an event to the UI. It blocks while the UI catches up so no
+// change is lost, but gives up once the informers that produced it are stopped.
+func (m *Manager) send(event ResourceEvent, stopCh <-chan struct{}) {
+	select {
+	case m.events <- event:
+	case <-stopCh:
+	}
+}
+
+// resetEvents discards queued events, which are stale once informers restart,
+// and queues a ResourcesReset. It never blocks, since it may run on the UI goroutine.
+func (m *Manager) resetEvents(namespace string, gen uint64) {
+	for {
+		select {
+		case <-m.events:
+			continue
+		default:
+		}
+		break
+	}
+
+	select {
+	case m.events <- ResourceEvent{Type: ResourcesReset, Namespace: namespace, Generation: gen}:
+	default:
+	}
+}
+
+// deletedObject unwraps the tombstone informers hand out when a delete was missed
+func deletedObject(obj interface{}) interface{} {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return tombstone.Obj
+	}
+	return obj
+}
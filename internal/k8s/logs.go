@@ -0,0 +1,346 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// logLineBuffer sizes the channel carrying streamed log lines
+const logLineBuffer = 256
+
+// defaultContainerAnnotation names the container kubectl uses when none is given
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// LogLine is a line of container output
+type LogLine struct {
+	Pod       string
+	Container string
+	Text      string
+	Notice    bool // Text describes a stream starting or ending, not output
+}
+
+// PodContainer is a container in a pod
+type PodContainer struct {
+	Name    string
+	Init    bool // an init container, which runs to completion before the others
+	Default bool // the container used when none is specified
+}
+
+// LogOptions controls which pod logs are read
+type LogOptions struct {
+	Container  string        // empty for the pod's default container
+	Previous   bool          // logs of the previous, terminated instance of the container
+	Timestamps bool          // prefix each line with its RFC3339 timestamp
+	TailLines  int64         // number of most recent lines, 0 for all
+	Since      time.Duration // only lines newer than this, 0 for no limit
+	SinceTime  time.Time     // only lines after this time; ignored when Since is set
+}
+
+// GetPodContainers returns a pod's containers followed by its init containers
+func (m *Manager) GetPodContainers(podName string) ([]PodContainer, error) {
+	clientset, namespace := m.client()
+	pod, err := clientset.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+
+	defaultName := defaultContainer(pod)
+	containers := make([]PodContainer, 0, len(pod.Spec.Containers)+len(pod.Spec.InitContainers))
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, PodContainer{Name: c.Name, Default: c.Name == defaultName})
+	}
+	for _, c := range pod.Spec.InitContainers {
+		containers = append(containers, PodContainer{Name: c.Name, Init: true})
+	}
+	return containers, nil
+}
+
+// FollowPodLogs streams a pod's logs until ctx is cancelled or the container
+// exits. The lines channel is closed when the stream ends.
+func (m *Manager) FollowPodLogs(ctx context.Context, podName string, opts LogOptions) (<-chan LogLine, error) {
+	clientset, namespace := m.client()
+	podLogOpts, err := podLogOptions(clientset, namespace, podName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := clientset.CoreV1().Pods(namespace).GetLogs(podName, podLogOpts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream logs for pod %s: %w", podName, err)
+	}
+
+	lines := make(chan LogLine, logLineBuffer)
+	go func() {
+		defer close(lines)
+		scanLogs(ctx, stream, func(text string) bool {
+			select {
+			case lines <- LogLine{Pod: podName, Container: podLogOpts.Container, Text: text}:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		})
+	}()
+
+	return lines, nil
+}
+
+// FollowDeploymentLogs streams logs from every container of every pod the
+// deployment selects, like stern. Streams are added as pods and containers
+// start and dropped as they go away, so a rollout is followed as it happens.
+// Timestamps, TailLines, Since and SinceTime apply to the pods running when
+// the stream starts; later ones are read from their beginning. Container and
+// Previous are ignored. The lines channel is closed once ctx is cancelled.
+func (m *Manager) FollowDeploymentLogs(ctx context.Context, deploymentName string, opts LogOptions) (<-chan LogLine, error) {
+	clientset, namespace := m.client()
+	deploy, err := clientset.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s: %w", deploymentName, err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector on deployment %s: %w", deploymentName, err)
+	}
+
+	agg := &logAggregator{
+		clientset: clientset,
+		ctx:       ctx,
+		namespace: namespace,
+		opts:      opts,
+		started:   time.Now(),
+		lines:     make(chan LogLine, logLineBuffer),
+		streams:   make(map[string]*containerStream),
+	}
+
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+			o.LabelSelector = selector.String()
+		}))
+	factory.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				agg.sync(pod)
+			}
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			if pod, ok := obj.(*corev1.Pod); ok {
+				agg.sync(pod)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if pod, ok := deletedObject(obj).(*corev1.Pod); ok {
+				agg.drop(pod.Name)
+			}
+		},
+	})
+	factory.Start(ctx.Done())
+
+	go func() {
+		<-ctx.Done()
+		factory.Shutdown()
+		agg.wg.Wait()
+		close(agg.lines)
+	}()
+
+	return agg.lines, nil
+}
+
+// containerStream is one container's log stream within a logAggregator
+type containerStream struct {
+	containerID string // a restarted container gets a new ID and a new stream
+	cancel      context.CancelFunc
+}
+
+// logAggregator fans the logs of many containers into one channel
+type logAggregator struct {
+	clientset *kubernetes.Clientset
+	ctx       context.Context
+	namespace string
+	opts      LogOptions
+	started   time.Time
+	lines     chan LogLine
+
+	mu      sync.Mutex
+	streams map[string]*containerStream // key: "pod/container"
+	wg      sync.WaitGroup
+}
+
+// sync starts streams for the pod's running containers that aren't streamed yet
+func (a *logAggregator) sync(pod *corev1.Pod) {
+	if pod.DeletionTimestamp != nil {
+		a.drop(pod.Name)
+		return
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Running == nil {
+			continue
+		}
+		a.start(pod.Name, status.Name, status.ContainerID, status.State.Running.StartedAt.Time)
+	}
+}
+
+// start streams a container's logs unless that instance is already streamed
+func (a *logAggregator) start(podName, container, containerID string, startedAt time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := podName + "/" + container
+	if s, ok := a.streams[key]; ok && s.containerID == containerID {
+		return
+	} else if ok {
+		s.cancel()
+	}
+	if a.ctx.Err() != nil {
+		return
+	}
+
+	// Containers that start after we began are read from their first line
+	opts := LogOptions{Container: container, Timestamps: a.opts.Timestamps}
+	if !startedAt.After(a.started) {
+		opts.TailLines, opts.Since, opts.SinceTime = a.opts.TailLines, a.opts.Since, a.opts.SinceTime
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	stream := &containerStream{containerID: containerID, cancel: cancel}
+	a.streams[key] = stream
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		defer a.finished(key, stream)
+		a.follow(ctx, podName, container, opts)
+	}()
+}
+
+// follow copies one container's log stream into the aggregated channel
+func (a *logAggregator) follow(ctx context.Context, podName, container string, opts LogOptions) {
+	podLogOpts, err := podLogOptions(a.clientset, a.namespace, podName, opts)
+	if err != nil {
+		a.emit(ctx, LogLine{Pod: podName, Container: container, Text: err.Error(), Notice: true})
+		return
+	}
+	stream, err := a.clientset.CoreV1().Pods(a.namespace).GetLogs(podName, podLogOpts).Stream(ctx)
+	if err != nil {
+		a.emit(ctx, LogLine{Pod: podName, Container: container, Text: fmt.Sprintf("failed to stream logs: %v", err), Notice: true})
+		return
+	}
+
+	if !a.emit(ctx, LogLine{Pod: podName, Container: container, Text: "+ streaming", Notice: true}) {
+		stream.Close()
+		return
+	}
+	scanLogs(ctx, stream, func(text string) bool {
+		return a.emit(ctx, LogLine{Pod: podName, Container: container, Text: text})
+	})
+	a.emit(ctx, LogLine{Pod: podName, Container: container, Text: "- stream ended", Notice: true})
+}
+
+// emit sends a line unless the stream is cancelled first
+func (a *logAggregator) emit(ctx context.Context, line LogLine) bool {
+	select {
+	case a.lines <- line:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// finished forgets a stream once it ends, so a restart can replace it
+func (a *logAggregator) finished(key string, stream *containerStream) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stream.cancel()
+	if a.streams[key] == stream {
+		delete(a.streams, key)
+	}
+}
+
+// drop stops every stream of a pod that has gone away
+func (a *logAggregator) drop(podName string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	prefix := podName + "/"
+	for key, s := range a.streams {
+		if strings.HasPrefix(key, prefix) {
+			s.cancel()
+		}
+	}
+}
+
+// scanLogs reads a log stream line by line until it ends or emit returns
+// false, then closes it
+func scanLogs(ctx context.Context, stream io.ReadCloser, emit func(string) bool) {
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if !emit(scanner.Text()) {
+			return
+		}
+	}
+}
+
+// podLogOptions converts LogOptions to the API's for a followed stream,
+// resolving the default container so multi-container pods don't fail with
+// "a container name must be specified"
+func podLogOptions(clientset *kubernetes.Clientset, namespace, podName string, opts LogOptions) (*corev1.PodLogOptions, error) {
+	container := opts.Container
+	if container == "" {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+		}
+		container = defaultContainer(pod)
+	}
+
+	podLogOpts := &corev1.PodLogOptions{
+		Container:  container,
+		Follow:     true,
+		Previous:   opts.Previous,
+		Timestamps: opts.Timestamps,
+	}
+	if opts.TailLines > 0 {
+		podLogOpts.TailLines = &opts.TailLines
+	}
+	if opts.Since > 0 {
+		seconds := int64(opts.Since.Seconds())
+		podLogOpts.SinceSeconds = &seconds
+	} else if !opts.SinceTime.IsZero() {
+		sinceTime := metav1.NewTime(opts.SinceTime)
+		podLogOpts.SinceTime = &sinceTime
+	}
+	return podLogOpts, nil
+}
+
+// defaultContainer picks the container kubectl would: the one named by the
+// default-container annotation, or else the first one
+func defaultContainer(pod *corev1.Pod) string {
+	if name := pod.Annotations[defaultContainerAnnotation]; name != "" {
+		for _, c := range pod.Spec.Containers {
+			if c.Name == name {
+				return name
+			}
+		}
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
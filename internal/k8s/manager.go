@@ -4,22 +4,24 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/craigderington/lazystack/internal/config"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
-	"sigs.k8s.io/yaml"
 )
 
-var _ = appsv1.Deployment{}         // Force import usage
 var _ = metricsv1beta1.PodMetrics{} // Force import usage
 
 // PodInfo represents a Kubernetes pod
@@ -54,99 +56,255 @@ type ServiceInfo struct {
 
 // PodMetrics represents resource usage metrics for a pod
 type PodMetrics struct {
-	Name              string
-	Namespace         string
-	CPUUsageMillis    int64  // CPU usage in millicores
-	MemoryUsageBytes  int64  // Memory usage in bytes
-	CPUUsageFormatted string // e.g., "250m"
+	Name                 string
+	Namespace            string
+	CPUUsageMillis       int64  // CPU usage in millicores
+	MemoryUsageBytes     int64  // Memory usage in bytes
+	CPUUsageFormatted    string // e.g., "250m"
 	MemoryUsageFormatted string // e.g., "512Mi"
+	Containers           []ContainerMetrics
+	CPURequestMillis     int64 // summed over containers
+	CPULimitMillis       int64 // 0 unless every container sets a limit
+	MemRequestBytes      int64
+	MemLimitBytes        int64
+}
+
+// ContainerMetrics is a container's resource usage next to its requests and
+// limits from the pod spec; a request or limit of 0 isn't set
+type ContainerMetrics struct {
+	Name             string
+	CPUMillis        int64
+	MemoryBytes      int64
+	CPURequestMillis int64
+	CPULimitMillis   int64
+	MemRequestBytes  int64
+	MemLimitBytes    int64
 }
 
 // EnvVar represents an environment variable
 type EnvVar struct {
-	Name       string
-	Value      string
-	ValueFrom  string // e.g., "ConfigMap: config-name", "Secret: secret-name"
+	Name      string
+	Value     string // the effective value, with references resolved
+	ValueFrom string // e.g., "ConfigMap: config-name (key: k)", "Secret: secret-name (key: k)"
+	Secret    bool   // Value comes from a Secret
+	Error     string // why the value couldn't be resolved, if it couldn't
 }
 
 // PodEnvVars represents all environment variables for a pod
 type PodEnvVars struct {
-	PodName    string
-	Namespace  string
-	Containers map[string][]EnvVar // container name -> env vars
+	PodName        string
+	Namespace      string
+	ContainerNames []string            // containers in spec order
+	Containers     map[string][]EnvVar // container name -> env vars
 }
 
-// PortForward represents an active port forward
-type PortForward struct {
-	PodName    string
-	LocalPort  string
-	RemotePort string
-	Cmd        *exec.Cmd
+// ContextInfo describes a context from the merged kubeconfig
+type ContextInfo struct {
+	Name      string
+	Cluster   string
+	User      string
+	Namespace string
+	Current   bool // the context the manager is connected to
 }
 
-// Manager handles Kubernetes interactions
-type Manager struct {
+// connection is the clients and namespace the manager works against. It is
+// replaced as a whole, so a snapshot never mixes two clusters.
+type connection struct {
 	clientset     *kubernetes.Clientset
 	metricsClient *metricsclientset.Clientset
+	restConfig    *rest.Config
 	namespace     string
 }
 
-// NewManager creates a new Kubernetes manager
-func NewManager() (*Manager, error) {
-	var kubeconfig string
+// Manager handles Kubernetes interactions
+type Manager struct {
+	loadingRules *clientcmd.ClientConfigLoadingRules
+
+	// Connection and informer state, guarded by mu. events outlives
+	// individual factories so the UI keeps a single subscription across
+	// namespace switches.
+	mu         sync.Mutex
+	conn       connection
+	context    string
+	factory    informers.SharedInformerFactory
+	stopCh     chan struct{}
+	generation uint64 // of the running informers, see ResourceEvent
+	events     chan ResourceEvent
+}
+
+// NewManager creates a new Kubernetes manager using the kubeconfig, context
+// and namespace from cfg
+func NewManager(cfg *config.Config) (*Manager, error) {
+	m := &Manager{
+		loadingRules: kubeconfigLoadingRules(cfg.Kubernetes.Kubeconfig),
+		events:       make(chan ResourceEvent, eventBuffer),
+	}
+
+	if err := m.connect(cfg.Kubernetes.DefaultContext); err != nil {
+		return nil, err
+	}
+
+	// A configured namespace wins over the one recorded in the context
+	if cfg.Kubernetes.DefaultNamespace != "" {
+		m.conn.namespace = cfg.Kubernetes.DefaultNamespace
+	}
+
+	return m, nil
+}
+
+// kubeconfigLoadingRules returns the rules for locating kubeconfig files.
+// An explicit path wins; otherwise every file in a colon-separated
+// $KUBECONFIG is merged, falling back to ~/.kube/config.
+func kubeconfigLoadingRules(explicitPath string) *clientcmd.ClientConfigLoadingRules {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if explicitPath != "" {
+		rules.ExplicitPath = explicitPath
+		return rules
+	}
 
-	// 1. Check KUBECONFIG environment variable
-	if envKubeconfig := os.Getenv("KUBECONFIG"); envKubeconfig != "" {
-		kubeconfig = envKubeconfig
-	} else {
-		// 2. If running as sudo, try to get the actual user's home directory
+	// If running as sudo, use the actual user's kubeconfig rather than root's
+	if os.Getenv("KUBECONFIG") == "" {
 		homeDir := homedir.HomeDir()
 		if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" && homeDir == "/root" {
-			// We're running with sudo, use the actual user's home
-			homeDir = filepath.Join("/home", sudoUser)
+			rules.Precedence = []string{filepath.Join("/home", sudoUser, ".kube", "config")}
 		}
-		kubeconfig = filepath.Join(homeDir, ".kube", "config")
 	}
 
-	// Build config from kubeconfig file
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	return rules
+}
+
+// connect builds clients for the named context ("" for the kubeconfig's
+// current-context) and switches the manager to them. m.mu must be held once
+// the manager is shared.
+func (m *Manager) connect(contextName string) error {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(m.loadingRules, overrides)
+
+	raw, err := clientConfig.RawConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	if contextName == "" {
+		contextName = raw.CurrentContext
+	}
+
+	restConfig, err := clientConfig.ClientConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build config from %s: %w", kubeconfig, err)
+		return fmt.Errorf("failed to build config for context %q: %w", contextName, err)
 	}
 
 	// Create clientset
-	clientset, err := kubernetes.NewForConfig(config)
+	clientset, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create clientset: %w", err)
+		return fmt.Errorf("failed to create clientset: %w", err)
 	}
 
 	// Create metrics clientset (may fail if metrics-server not installed)
-	metricsClient, err := metricsclientset.NewForConfig(config)
+	metricsClient, err := metricsclientset.NewForConfig(restConfig)
 	if err != nil {
 		// Don't fail if metrics client can't be created - metrics just won't be available
 		metricsClient = nil
 	}
 
-	return &Manager{
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil || namespace == "" {
+		namespace = "default"
+	}
+
+	m.conn = connection{
 		clientset:     clientset,
 		metricsClient: metricsClient,
-		namespace:     "default",
-	}, nil
+		restConfig:    restConfig,
+		namespace:     namespace,
+	}
+	m.context = contextName
+	return nil
+}
+
+// current returns a snapshot of the connection, safe to use from any goroutine
+func (m *Manager) current() connection {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.conn
+}
+
+// client returns the clientset and namespace of one connection snapshot
+func (m *Manager) client() (*kubernetes.Clientset, string) {
+	conn := m.current()
+	return conn.clientset, conn.namespace
 }
 
-// SetNamespace sets the current namespace
+// ListContexts returns every context in the merged kubeconfig, sorted by name
+func (m *Manager) ListContexts() ([]ContextInfo, error) {
+	raw, err := m.loadingRules.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	contexts := make([]ContextInfo, 0, len(raw.Contexts))
+	for name, ctx := range raw.Contexts {
+		contexts = append(contexts, ContextInfo{
+			Name:      name,
+			Cluster:   ctx.Cluster,
+			User:      ctx.AuthInfo,
+			Namespace: ctx.Namespace,
+			Current:   name == m.GetContext(),
+		})
+	}
+
+	sort.Slice(contexts, func(i, j int) bool {
+		return contexts[i].Name < contexts[j].Name
+	})
+
+	return contexts, nil
+}
+
+// GetContext returns the name of the context the manager is connected to
+func (m *Manager) GetContext() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.context
+}
+
+// SwitchContext reconnects to another kubeconfig context without restarting.
+// The namespace resets to the context's default and running informers are
+// restarted against the new cluster.
+func (m *Manager) SwitchContext(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.connect(name); err != nil {
+		return err
+	}
+	if m.factory != nil {
+		m.startInformersLocked()
+	}
+	return nil
+}
+
+// SetNamespace sets the current namespace, restarting informers if they are running
 func (m *Manager) SetNamespace(namespace string) {
-	m.namespace = namespace
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.conn.namespace = namespace
+	if m.factory != nil {
+		m.startInformersLocked()
+	}
 }
 
 // GetNamespace returns the current namespace
 func (m *Manager) GetNamespace() string {
-	return m.namespace
+	_, namespace := m.client()
+	return namespace
 }
 
 // ListNamespaces returns a list of all namespaces
 func (m *Manager) ListNamespaces() ([]string, error) {
-	namespaces, err := m.clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	clientset, _ := m.client()
+	namespaces, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list namespaces: %w", err)
 	}
@@ -161,29 +319,79 @@ func (m *Manager) ListNamespaces() ([]string, error) {
 
 // ListPods returns a list of pods in the current namespace
 func (m *Manager) ListPods() ([]PodInfo, error) {
-	pods, err := m.clientset.CoreV1().Pods(m.namespace).List(context.Background(), metav1.ListOptions{})
+	clientset, namespace := m.client()
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods: %w", err)
 	}
 
 	podInfos := make([]PodInfo, 0, len(pods.Items))
-	for _, pod := range pods.Items {
-		podInfo := PodInfo{
-			Name:      pod.Name,
-			Namespace: pod.Namespace,
-			Status:    string(pod.Status.Phase),
-			Ready:     m.getPodReadyStatus(&pod),
-			Restarts:  m.getPodRestarts(&pod),
-		}
-		podInfos = append(podInfos, podInfo)
+	for i := range pods.Items {
+		podInfos = append(podInfos, toPodInfo(&pods.Items[i]))
 	}
 
 	return podInfos, nil
 }
 
+// toPodInfo converts a pod into a PodInfo
+func toPodInfo(pod *corev1.Pod) PodInfo {
+	return PodInfo{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Status:    getPodStatus(pod),
+		Ready:     getPodReadyStatus(pod),
+		Restarts:  getPodRestarts(pod),
+		Age:       formatAge(pod.CreationTimestamp.Time),
+	}
+}
+
+// getPodStatus returns the pod status the way kubectl reports it, preferring
+// container waiting/terminated reasons (e.g. CrashLoopBackOff) over the phase
+func getPodStatus(pod *corev1.Pod) string {
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+
+	status := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		status = pod.Status.Reason
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return cs.State.Waiting.Reason
+		}
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			status = cs.State.Terminated.Reason
+		}
+	}
+
+	return status
+}
+
+// formatAge returns a short human-readable age such as "5m" or "3d"
+func formatAge(created time.Time) string {
+	if created.IsZero() {
+		return ""
+	}
+
+	d := time.Since(created)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
 // DeletePod deletes a pod by name
 func (m *Manager) DeletePod(name string) error {
-	err := m.clientset.CoreV1().Pods(m.namespace).Delete(
+	clientset, namespace := m.client()
+	err := clientset.CoreV1().Pods(namespace).Delete(
 		context.Background(),
 		name,
 		metav1.DeleteOptions{},
@@ -194,23 +402,8 @@ func (m *Manager) DeletePod(name string) error {
 	return nil
 }
 
-// GetPodLogs returns logs for a specific pod
-func (m *Manager) GetPodLogs(name string, lines int64) (string, error) {
-	podLogOpts := corev1.PodLogOptions{
-		TailLines: &lines,
-	}
-
-	req := m.clientset.CoreV1().Pods(m.namespace).GetLogs(name, &podLogOpts)
-	logs, err := req.DoRaw(context.Background())
-	if err != nil {
-		return "", fmt.Errorf("failed to get logs for pod %s: %w", name, err)
-	}
-
-	return string(logs), nil
-}
-
 // getPodReadyStatus returns a string representing the ready status (e.g., "2/3")
-func (m *Manager) getPodReadyStatus(pod *corev1.Pod) string {
+func getPodReadyStatus(pod *corev1.Pod) string {
 	totalContainers := len(pod.Status.ContainerStatuses)
 	readyContainers := 0
 
@@ -224,7 +417,7 @@ func (m *Manager) getPodReadyStatus(pod *corev1.Pod) string {
 }
 
 // getPodRestarts returns the total number of container restarts in a pod
-func (m *Manager) getPodRestarts(pod *corev1.Pod) int32 {
+func getPodRestarts(pod *corev1.Pod) int32 {
 	var restarts int32
 	for _, status := range pod.Status.ContainerStatuses {
 		restarts += status.RestartCount
@@ -234,181 +427,149 @@ func (m *Manager) getPodRestarts(pod *corev1.Pod) int32 {
 
 // ListDeployments returns a list of deployments in the current namespace
 func (m *Manager) ListDeployments() ([]DeploymentInfo, error) {
-	deployments, err := m.clientset.AppsV1().Deployments(m.namespace).List(context.Background(), metav1.ListOptions{})
+	clientset, namespace := m.client()
+	deployments, err := clientset.AppsV1().Deployments(namespace).List(context.Background(), metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list deployments: %w", err)
 	}
 
 	deploymentInfos := make([]DeploymentInfo, 0, len(deployments.Items))
-	for _, deploy := range deployments.Items {
-		ready := fmt.Sprintf("%d/%d", deploy.Status.ReadyReplicas, deploy.Status.Replicas)
-		deploymentInfo := DeploymentInfo{
-			Name:      deploy.Name,
-			Namespace: deploy.Namespace,
-			Ready:     ready,
-			UpToDate:  deploy.Status.UpdatedReplicas,
-			Available: deploy.Status.AvailableReplicas,
-			Replicas:  deploy.Status.Replicas,
-		}
-		deploymentInfos = append(deploymentInfos, deploymentInfo)
+	for i := range deployments.Items {
+		deploymentInfos = append(deploymentInfos, toDeploymentInfo(&deployments.Items[i]))
 	}
 
 	return deploymentInfos, nil
 }
 
+// toDeploymentInfo converts a deployment into a DeploymentInfo
+func toDeploymentInfo(deploy *appsv1.Deployment) DeploymentInfo {
+	return DeploymentInfo{
+		Name:      deploy.Name,
+		Namespace: deploy.Namespace,
+		Ready:     fmt.Sprintf("%d/%d", deploy.Status.ReadyReplicas, deploy.Status.Replicas),
+		UpToDate:  deploy.Status.UpdatedReplicas,
+		Available: deploy.Status.AvailableReplicas,
+		Replicas:  deploy.Status.Replicas,
+	}
+}
+
 // ListServices returns a list of Kubernetes services in the current namespace
 func (m *Manager) ListServices() ([]ServiceInfo, error) {
-	services, err := m.clientset.CoreV1().Services(m.namespace).List(context.Background(), metav1.ListOptions{})
+	clientset, namespace := m.client()
+	services, err := clientset.CoreV1().Services(namespace).List(context.Background(), metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list services: %w", err)
 	}
 
 	serviceInfos := make([]ServiceInfo, 0, len(services.Items))
-	for _, svc := range services.Items {
-		// Build ports string
-		ports := ""
-		for i, port := range svc.Spec.Ports {
-			if i > 0 {
-				ports += ","
-			}
-			ports += fmt.Sprintf("%d/%s", port.Port, port.Protocol)
-		}
+	for i := range services.Items {
+		serviceInfos = append(serviceInfos, toServiceInfo(&services.Items[i]))
+	}
 
-		// Get external IP
-		externalIP := "<none>"
-		if len(svc.Status.LoadBalancer.Ingress) > 0 {
-			if svc.Status.LoadBalancer.Ingress[0].IP != "" {
-				externalIP = svc.Status.LoadBalancer.Ingress[0].IP
-			} else if svc.Status.LoadBalancer.Ingress[0].Hostname != "" {
-				externalIP = svc.Status.LoadBalancer.Ingress[0].Hostname
-			}
-		} else if len(svc.Spec.ExternalIPs) > 0 {
-			externalIP = svc.Spec.ExternalIPs[0]
+	return serviceInfos, nil
+}
+
+// toServiceInfo converts a service into a ServiceInfo
+func toServiceInfo(svc *corev1.Service) ServiceInfo {
+	// Build ports string
+	ports := ""
+	for i, port := range svc.Spec.Ports {
+		if i > 0 {
+			ports += ","
 		}
+		ports += fmt.Sprintf("%d/%s", port.Port, port.Protocol)
+	}
 
-		serviceInfo := ServiceInfo{
-			Name:       svc.Name,
-			Namespace:  svc.Namespace,
-			Type:       string(svc.Spec.Type),
-			ClusterIP:  svc.Spec.ClusterIP,
-			ExternalIP: externalIP,
-			Ports:      ports,
+	// Get external IP
+	externalIP := "<none>"
+	if len(svc.Status.LoadBalancer.Ingress) > 0 {
+		if svc.Status.LoadBalancer.Ingress[0].IP != "" {
+			externalIP = svc.Status.LoadBalancer.Ingress[0].IP
+		} else if svc.Status.LoadBalancer.Ingress[0].Hostname != "" {
+			externalIP = svc.Status.LoadBalancer.Ingress[0].Hostname
 		}
-		serviceInfos = append(serviceInfos, serviceInfo)
+	} else if len(svc.Spec.ExternalIPs) > 0 {
+		externalIP = svc.Spec.ExternalIPs[0]
 	}
 
-	return serviceInfos, nil
+	return ServiceInfo{
+		Name:       svc.Name,
+		Namespace:  svc.Namespace,
+		Type:       string(svc.Spec.Type),
+		ClusterIP:  svc.Spec.ClusterIP,
+		ExternalIP: externalIP,
+		Ports:      ports,
+	}
 }
 
 // GetPodMetrics returns resource usage metrics for a specific pod
 func (m *Manager) GetPodMetrics(podName string) (*PodMetrics, error) {
-	if m.metricsClient == nil {
+	conn := m.current()
+	if conn.metricsClient == nil {
 		return nil, fmt.Errorf("metrics-server not available")
 	}
 
-	podMetrics, err := m.metricsClient.MetricsV1beta1().PodMetricses(m.namespace).Get(context.Background(), podName, metav1.GetOptions{})
+	podMetrics, err := conn.metricsClient.MetricsV1beta1().PodMetricses(conn.namespace).Get(context.Background(), podName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get metrics for pod %s: %w", podName, err)
 	}
 
-	// Aggregate metrics across all containers in the pod
-	var totalCPU int64
-	var totalMemory int64
-
-	for _, container := range podMetrics.Containers {
-		cpuQuantity := container.Usage.Cpu()
-		memQuantity := container.Usage.Memory()
-
-		totalCPU += cpuQuantity.MilliValue()
-		totalMemory += memQuantity.Value()
+	pod, err := conn.clientset.CoreV1().Pods(conn.namespace).Get(context.Background(), podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
 	}
 
-	// Format CPU (millicores to readable format)
-	cpuFormatted := fmt.Sprintf("%dm", totalCPU)
-	if totalCPU >= 1000 {
-		cpuFormatted = fmt.Sprintf("%.2f", float64(totalCPU)/1000.0)
+	// Requests and limits come from the spec, in the spec's container order
+	result := &PodMetrics{
+		Name:      podName,
+		Namespace: conn.namespace,
 	}
+	result.CPURequestMillis, result.CPULimitMillis, result.MemRequestBytes, result.MemLimitBytes = podResources(pod)
 
-	// Format Memory (bytes to Mi/Gi)
-	memFormatted := fmt.Sprintf("%dMi", totalMemory/(1024*1024))
-	if totalMemory >= 1024*1024*1024 {
-		memFormatted = fmt.Sprintf("%.2fGi", float64(totalMemory)/(1024*1024*1024))
+	usage := make(map[string]corev1.ResourceList, len(podMetrics.Containers))
+	for _, container := range podMetrics.Containers {
+		usage[container.Name] = container.Usage
+	}
+	for _, c := range pod.Spec.Containers {
+		cm := ContainerMetrics{
+			Name:             c.Name,
+			CPURequestMillis: c.Resources.Requests.Cpu().MilliValue(),
+			CPULimitMillis:   c.Resources.Limits.Cpu().MilliValue(),
+			MemRequestBytes:  c.Resources.Requests.Memory().Value(),
+			MemLimitBytes:    c.Resources.Limits.Memory().Value(),
+		}
+		if u, ok := usage[c.Name]; ok {
+			cm.CPUMillis = u.Cpu().MilliValue()
+			cm.MemoryBytes = u.Memory().Value()
+		}
+		result.CPUUsageMillis += cm.CPUMillis
+		result.MemoryUsageBytes += cm.MemoryBytes
+		result.Containers = append(result.Containers, cm)
 	}
 
-	return &PodMetrics{
-		Name:                 podName,
-		Namespace:            m.namespace,
-		CPUUsageMillis:       totalCPU,
-		MemoryUsageBytes:     totalMemory,
-		CPUUsageFormatted:    cpuFormatted,
-		MemoryUsageFormatted: memFormatted,
-	}, nil
+	result.CPUUsageFormatted = FormatCPU(result.CPUUsageMillis)
+	result.MemoryUsageFormatted = FormatMemory(result.MemoryUsageBytes)
+	return result, nil
 }
 
 // GetPodEnvVars returns all environment variables for a specific pod
 func (m *Manager) GetPodEnvVars(podName string) (*PodEnvVars, error) {
-	pod, err := m.clientset.CoreV1().Pods(m.namespace).Get(context.Background(), podName, metav1.GetOptions{})
+	clientset, namespace := m.client()
+	pod, err := clientset.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
 	}
 
 	result := &PodEnvVars{
 		PodName:    podName,
-		Namespace:  m.namespace,
+		Namespace:  namespace,
 		Containers: make(map[string][]EnvVar),
 	}
 
-	// Iterate through all containers in the pod
+	sources := newEnvSources(clientset, namespace)
 	for _, container := range pod.Spec.Containers {
-		envVars := []EnvVar{}
-
-		for _, env := range container.Env {
-			envVar := EnvVar{
-				Name: env.Name,
-			}
-
-			// Check if value is directly specified
-			if env.Value != "" {
-				envVar.Value = env.Value
-			} else if env.ValueFrom != nil {
-				// Value comes from ConfigMap, Secret, FieldRef, or ResourceFieldRef
-				if env.ValueFrom.ConfigMapKeyRef != nil {
-					envVar.ValueFrom = fmt.Sprintf("ConfigMap: %s (key: %s)",
-						env.ValueFrom.ConfigMapKeyRef.Name,
-						env.ValueFrom.ConfigMapKeyRef.Key)
-				} else if env.ValueFrom.SecretKeyRef != nil {
-					envVar.ValueFrom = fmt.Sprintf("Secret: %s (key: %s)",
-						env.ValueFrom.SecretKeyRef.Name,
-						env.ValueFrom.SecretKeyRef.Key)
-					envVar.Value = "<secret>"
-				} else if env.ValueFrom.FieldRef != nil {
-					envVar.ValueFrom = fmt.Sprintf("FieldRef: %s", env.ValueFrom.FieldRef.FieldPath)
-				} else if env.ValueFrom.ResourceFieldRef != nil {
-					envVar.ValueFrom = fmt.Sprintf("ResourceFieldRef: %s", env.ValueFrom.ResourceFieldRef.Resource)
-				}
-			}
-
-			envVars = append(envVars, envVar)
-		}
-
-		// Also check for envFrom (ConfigMapRef, SecretRef)
-		for _, envFrom := range container.EnvFrom {
-			if envFrom.ConfigMapRef != nil {
-				envVars = append(envVars, EnvVar{
-					Name:      fmt.Sprintf("(All keys from ConfigMap: %s)", envFrom.ConfigMapRef.Name),
-					ValueFrom: fmt.Sprintf("ConfigMap: %s", envFrom.ConfigMapRef.Name),
-				})
-			}
-			if envFrom.SecretRef != nil {
-				envVars = append(envVars, EnvVar{
-					Name:      fmt.Sprintf("(All keys from Secret: %s)", envFrom.SecretRef.Name),
-					ValueFrom: fmt.Sprintf("Secret: %s", envFrom.SecretRef.Name),
-					Value:     "<secret>",
-				})
-			}
-		}
-
-		result.Containers[container.Name] = envVars
+		result.ContainerNames = append(result.ContainerNames, container.Name)
+		result.Containers[container.Name] = containerEnv(pod, container, sources)
 	}
 
 	return result, nil
@@ -416,62 +577,30 @@ func (m *Manager) GetPodEnvVars(podName string) (*PodEnvVars, error) {
 
 // GetPodYAML returns the YAML representation of a pod
 func (m *Manager) GetPodYAML(podName string) (string, error) {
-	pod, err := m.clientset.CoreV1().Pods(m.namespace).Get(context.Background(), podName, metav1.GetOptions{})
-	if err != nil {
-		return "", fmt.Errorf("failed to get pod %s: %w", podName, err)
-	}
-
-	// Convert to YAML
-	yamlBytes, err := yaml.Marshal(pod)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal pod to YAML: %w", err)
-	}
-
-	return string(yamlBytes), nil
+	return m.getResourceYAML("pod", podName)
 }
 
 // GetDeploymentYAML returns the YAML representation of a deployment
 func (m *Manager) GetDeploymentYAML(deploymentName string) (string, error) {
-	deployment, err := m.clientset.AppsV1().Deployments(m.namespace).Get(context.Background(), deploymentName, metav1.GetOptions{})
-	if err != nil {
-		return "", fmt.Errorf("failed to get deployment %s: %w", deploymentName, err)
-	}
-
-	// Convert to YAML
-	yamlBytes, err := yaml.Marshal(deployment)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal deployment to YAML: %w", err)
-	}
-
-	return string(yamlBytes), nil
+	return m.getResourceYAML("deployment", deploymentName)
 }
 
 // GetServiceYAML returns the YAML representation of a service
 func (m *Manager) GetServiceYAML(serviceName string) (string, error) {
-	service, err := m.clientset.CoreV1().Services(m.namespace).Get(context.Background(), serviceName, metav1.GetOptions{})
-	if err != nil {
-		return "", fmt.Errorf("failed to get service %s: %w", serviceName, err)
-	}
-
-	// Convert to YAML
-	yamlBytes, err := yaml.Marshal(service)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal service to YAML: %w", err)
-	}
-
-	return string(yamlBytes), nil
+	return m.getResourceYAML("service", serviceName)
 }
 
 // ScaleDeployment scales a deployment to the specified number of replicas
 func (m *Manager) ScaleDeployment(name string, replicas int32) error {
-	deployment, err := m.clientset.AppsV1().Deployments(m.namespace).Get(context.Background(), name, metav1.GetOptions{})
+	clientset, namespace := m.client()
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to get deployment %s: %w", name, err)
 	}
 
 	deployment.Spec.Replicas = &replicas
 
-	_, err = m.clientset.AppsV1().Deployments(m.namespace).Update(context.Background(), deployment, metav1.UpdateOptions{})
+	_, err = clientset.AppsV1().Deployments(namespace).Update(context.Background(), deployment, metav1.UpdateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to scale deployment %s: %w", name, err)
 	}
@@ -481,7 +610,8 @@ func (m *Manager) ScaleDeployment(name string, replicas int32) error {
 
 // RestartDeployment restarts a deployment by updating its annotation
 func (m *Manager) RestartDeployment(name string) error {
-	deployment, err := m.clientset.AppsV1().Deployments(m.namespace).Get(context.Background(), name, metav1.GetOptions{})
+	clientset, namespace := m.client()
+	deployment, err := clientset.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to get deployment %s: %w", name, err)
 	}
@@ -492,7 +622,7 @@ func (m *Manager) RestartDeployment(name string) error {
 	}
 	deployment.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = time.Now().Format(time.RFC3339)
 
-	_, err = m.clientset.AppsV1().Deployments(m.namespace).Update(context.Background(), deployment, metav1.UpdateOptions{})
+	_, err = clientset.AppsV1().Deployments(namespace).Update(context.Background(), deployment, metav1.UpdateOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to restart deployment %s: %w", name, err)
 	}
@@ -502,7 +632,8 @@ func (m *Manager) RestartDeployment(name string) error {
 
 // DeleteDeployment deletes a deployment by name
 func (m *Manager) DeleteDeployment(name string) error {
-	err := m.clientset.AppsV1().Deployments(m.namespace).Delete(
+	clientset, namespace := m.client()
+	err := clientset.AppsV1().Deployments(namespace).Delete(
 		context.Background(),
 		name,
 		metav1.DeleteOptions{},
@@ -512,42 +643,3 @@ func (m *Manager) DeleteDeployment(name string) error {
 	}
 	return nil
 }
-
-// StartPortForward starts a port forward to a pod or service
-// Returns the PortForward struct which can be used to stop it later
-func (m *Manager) StartPortForward(podName, localPort, remotePort string) (*PortForward, error) {
-	// Use kubectl port-forward command for simplicity
-	cmd := exec.Command("kubectl", "port-forward",
-		"-n", m.namespace,
-		fmt.Sprintf("pod/%s", podName),
-		fmt.Sprintf("%s:%s", localPort, remotePort))
-
-	// Start the command in the background
-	err := cmd.Start()
-	if err != nil {
-		return nil, fmt.Errorf("failed to start port-forward: %w", err)
-	}
-
-	pf := &PortForward{
-		PodName:    podName,
-		LocalPort:  localPort,
-		RemotePort: remotePort,
-		Cmd:        cmd,
-	}
-
-	return pf, nil
-}
-
-// StopPortForward stops an active port forward
-func (m *Manager) StopPortForward(pf *PortForward) error {
-	if pf == nil || pf.Cmd == nil || pf.Cmd.Process == nil {
-		return fmt.Errorf("invalid port forward")
-	}
-
-	err := pf.Cmd.Process.Kill()
-	if err != nil {
-		return fmt.Errorf("failed to stop port-forward: %w", err)
-	}
-
-	return nil
-}
@@ -0,0 +1,168 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// PodUsage is a pod's resource usage next to its requests and limits.
+// Requests and limits are summed over the containers; a limit is 0 unless
+// every container sets one, since the pod is unbounded otherwise.
+type PodUsage struct {
+	Name             string
+	Node             string
+	CPUMillis        int64
+	MemoryBytes      int64
+	CPURequestMillis int64
+	CPULimitMillis   int64
+	MemRequestBytes  int64
+	MemLimitBytes    int64
+}
+
+// NodeUsage is a node's resource usage next to its allocatable capacity
+type NodeUsage struct {
+	Name                 string
+	CPUMillis            int64
+	MemoryBytes          int64
+	CPUAllocatableMillis int64
+	MemAllocatableBytes  int64
+}
+
+// ListPodUsage returns the usage of every pod in the current namespace that
+// metrics-server has a sample for. Nodes, requests and limits come from the
+// informers' cache, so only the metrics are fetched on each call.
+func (m *Manager) ListPodUsage() ([]PodUsage, error) {
+	conn, lister := m.podCache()
+	if conn.metricsClient == nil {
+		return nil, fmt.Errorf("metrics-server not available")
+	}
+
+	ctx := context.Background()
+	metrics, err := conn.metricsClient.MetricsV1beta1().PodMetricses(conn.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pod metrics: %w", err)
+	}
+
+	var pods []*corev1.Pod
+	if lister != nil {
+		pods, err = lister.Pods(conn.namespace).List(labels.Everything())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+	} else {
+		list, err := conn.clientset.CoreV1().Pods(conn.namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %w", err)
+		}
+		for i := range list.Items {
+			pods = append(pods, &list.Items[i])
+		}
+	}
+
+	byName := make(map[string]*corev1.Pod, len(pods))
+	for _, pod := range pods {
+		byName[pod.Name] = pod
+	}
+
+	usage := make([]PodUsage, 0, len(metrics.Items))
+	for _, pm := range metrics.Items {
+		u := PodUsage{Name: pm.Name}
+		for _, c := range pm.Containers {
+			u.CPUMillis += c.Usage.Cpu().MilliValue()
+			u.MemoryBytes += c.Usage.Memory().Value()
+		}
+		if pod, ok := byName[pm.Name]; ok {
+			u.Node = pod.Spec.NodeName
+			u.CPURequestMillis, u.CPULimitMillis, u.MemRequestBytes, u.MemLimitBytes = podResources(pod)
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+// ListNodeUsage returns the usage of every node metrics-server has a sample for
+func (m *Manager) ListNodeUsage() ([]NodeUsage, error) {
+	conn := m.current()
+	if conn.metricsClient == nil {
+		return nil, fmt.Errorf("metrics-server not available")
+	}
+
+	ctx := context.Background()
+	metrics, err := conn.metricsClient.MetricsV1beta1().NodeMetricses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node metrics: %w", err)
+	}
+	nodes, err := conn.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+
+	allocatable := make(map[string]corev1.ResourceList, len(nodes.Items))
+	for _, node := range nodes.Items {
+		allocatable[node.Name] = node.Status.Allocatable
+	}
+
+	usage := make([]NodeUsage, 0, len(metrics.Items))
+	for _, nm := range metrics.Items {
+		u := NodeUsage{
+			Name:        nm.Name,
+			CPUMillis:   nm.Usage.Cpu().MilliValue(),
+			MemoryBytes: nm.Usage.Memory().Value(),
+		}
+		if alloc, ok := allocatable[nm.Name]; ok {
+			u.CPUAllocatableMillis = alloc.Cpu().MilliValue()
+			u.MemAllocatableBytes = alloc.Memory().Value()
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+// podResources sums the CPU and memory requests and limits of a pod's
+// containers. A limit is 0 if any container doesn't set it.
+func podResources(pod *corev1.Pod) (cpuRequest, cpuLimit, memRequest, memLimit int64) {
+	cpuLimited, memLimited := true, true
+	for _, c := range pod.Spec.Containers {
+		cpuRequest += c.Resources.Requests.Cpu().MilliValue()
+		memRequest += c.Resources.Requests.Memory().Value()
+
+		if l, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+			cpuLimit += l.MilliValue()
+		} else {
+			cpuLimited = false
+		}
+		if l, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+			memLimit += l.Value()
+		} else {
+			memLimited = false
+		}
+	}
+
+	if !cpuLimited {
+		cpuLimit = 0
+	}
+	if !memLimited {
+		memLimit = 0
+	}
+	return cpuRequest, cpuLimit, memRequest, memLimit
+}
+
+// FormatCPU formats millicores like kubectl top, e.g. "250m" or "1.50"
+func FormatCPU(millis int64) string {
+	if millis >= 1000 {
+		return fmt.Sprintf("%.2f", float64(millis)/1000.0)
+	}
+	return fmt.Sprintf("%dm", millis)
+}
+
+// FormatMemory formats bytes as Mi, or Gi from 1Gi up
+func FormatMemory(bytes int64) string {
+	if bytes >= 1024*1024*1024 {
+		return fmt.Sprintf("%.2fGi", float64(bytes)/(1024*1024*1024))
+	}
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
@@ -0,0 +1,711 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// portForwardTimeout bounds how long we wait for a forward to start listening
+const portForwardTimeout = 30 * time.Second
+
+// Reconnect backoff: the delay doubles from the base up to the max, and the
+// forward fails after maxReconnectAttempts consecutive failed attempts
+const (
+	reconnectBaseDelay   = time.Second
+	reconnectMaxDelay    = 30 * time.Second
+	maxReconnectAttempts = 10
+)
+
+// portForwardEventBuffer sizes each forward's event channel; events are
+// dropped rather than stalling the supervisor when nobody is reading
+const portForwardEventBuffer = 16
+
+// PortForwardState is the lifecycle state of a port forward
+type PortForwardState int
+
+const (
+	PortForwardStarting PortForwardState = iota
+	PortForwardActive
+	PortForwardReconnecting // the connection was lost and is being re-established
+	PortForwardFailed       // reconnecting gave up
+	PortForwardStopped      // stopped with StopPortForward
+)
+
+// String returns a short human-readable name for the state
+func (s PortForwardState) String() string {
+	switch s {
+	case PortForwardStarting:
+		return "starting"
+	case PortForwardActive:
+		return "active"
+	case PortForwardReconnecting:
+		return "reconnecting"
+	case PortForwardFailed:
+		return "failed"
+	case PortForwardStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// ContainerPort is a port declared in a pod's container spec
+type ContainerPort struct {
+	Container string
+	Name      string // may be empty
+	Port      int32
+	Protocol  string // TCP, UDP or SCTP; only TCP can be forwarded
+}
+
+// ServicePort is a port exposed by a service
+type ServicePort struct {
+	Name       string // may be empty
+	Port       int32
+	TargetPort string // number or container port name on the backing pods
+	Protocol   string
+}
+
+// PortForwardStatus is a snapshot of a forward's health
+type PortForwardStatus struct {
+	State       PortForwardState
+	PodName     string // the pod currently receiving connections
+	Connections int64  // local connections handled across all sessions
+	Reconnects  int    // successful reconnects
+	LastError   error  // most recent failure, kept after a successful reconnect
+}
+
+// PortForwardEventType describes a change in a forward's connection
+type PortForwardEventType int
+
+const (
+	PortForwardDisconnected PortForwardEventType = iota
+	PortForwardReconnected
+)
+
+// PortForwardEvent is sent when a forward loses or regains its connection
+type PortForwardEvent struct {
+	Type    PortForwardEventType
+	PodName string // the new pod for PortForwardReconnected
+	Err     error  // why the connection was lost, for PortForwardDisconnected
+}
+
+// PortForward represents a port forward from localhost to a pod, or to a
+// service through one of its ready pods. A supervisor re-establishes it with
+// backoff whenever the connection to the pod is lost.
+type PortForward struct {
+	Namespace  string
+	Service    string // set for service forwards
+	Selector   string // set for forwards to pods matching a label selector
+	LocalPort  string
+	RemotePort string // the pod port, or the service port for service forwards
+
+	mu          sync.Mutex
+	podName     string
+	state       PortForwardState
+	lastErr     error
+	connections int64
+	reconnects  int
+	events      chan PortForwardEvent
+	stopCh      chan struct{}
+	stopOnce    sync.Once
+	doneCh      chan struct{}
+
+	// conn is the cluster the forward was started on; reconnects stay on it
+	conn connection
+
+	// resolve picks the pod and pod port to (re)connect to
+	resolve func() (podName, podPort string, err error)
+}
+
+// Target names what is forwarded: the pod, "svc/<name>" for services, or
+// the label selector
+func (pf *PortForward) Target() string {
+	switch {
+	case pf.Service != "":
+		return "svc/" + pf.Service
+	case pf.Selector != "":
+		return pf.Selector
+	}
+	return pf.PodName()
+}
+
+// PodName returns the pod currently receiving forwarded connections
+func (pf *PortForward) PodName() string {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	return pf.podName
+}
+
+// State returns the current lifecycle state
+func (pf *PortForward) State() PortForwardState {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	return pf.state
+}
+
+// Status returns a snapshot of the forward's state, counters and last error
+func (pf *PortForward) Status() PortForwardStatus {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	return PortForwardStatus{
+		State:       pf.state,
+		PodName:     pf.podName,
+		Connections: pf.connections,
+		Reconnects:  pf.reconnects,
+		LastError:   pf.lastErr,
+	}
+}
+
+// Events delivers connection losses and reconnects. Events are dropped when
+// the channel is full, so consumers should treat them as notifications only.
+func (pf *PortForward) Events() <-chan PortForwardEvent {
+	return pf.events
+}
+
+// Done is closed once the forward has ended, whether it failed or was stopped
+func (pf *PortForward) Done() <-chan struct{} {
+	return pf.doneCh
+}
+
+// setState records a state change and, if err is set, the error behind it
+func (pf *PortForward) setState(state PortForwardState, err error) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	pf.state = state
+	if err != nil {
+		pf.lastErr = err
+	}
+}
+
+// recordError remembers a failed reconnect attempt
+func (pf *PortForward) recordError(err error) {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	pf.lastErr = err
+}
+
+// disconnected marks the forward as reconnecting after losing its session
+func (pf *PortForward) disconnected(err error) {
+	pf.setState(PortForwardReconnecting, err)
+	pf.emit(PortForwardEvent{Type: PortForwardDisconnected, PodName: pf.PodName(), Err: err})
+}
+
+// reconnected records a new session to podName
+func (pf *PortForward) reconnected(podName string) {
+	pf.mu.Lock()
+	pf.podName = podName
+	pf.state = PortForwardActive
+	pf.reconnects++
+	pf.mu.Unlock()
+
+	pf.emit(PortForwardEvent{Type: PortForwardReconnected, PodName: podName})
+}
+
+// emit sends an event without blocking the supervisor
+func (pf *PortForward) emit(event PortForwardEvent) {
+	select {
+	case pf.events <- event:
+	default:
+	}
+}
+
+// connectionCounter counts the "Handling connection for <port>" lines a
+// forwarder writes to its output, one per accepted local connection
+type connectionCounter struct {
+	pf *PortForward
+}
+
+func (c connectionCounter) Write(p []byte) (int, error) {
+	c.pf.mu.Lock()
+	defer c.pf.mu.Unlock()
+	c.pf.connections += int64(bytes.Count(p, []byte("Handling connection")))
+	return len(p), nil
+}
+
+// stop asks the forwarder to shut down; safe to call more than once
+func (pf *PortForward) stop() {
+	pf.stopOnce.Do(func() { close(pf.stopCh) })
+}
+
+// stopped reports whether StopPortForward has been called
+func (pf *PortForward) stopped() bool {
+	select {
+	case <-pf.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// StartPortForward forwards localPort on localhost to remotePort on a pod.
+// An empty namespace means the current one. It returns once the local port is
+// listening, or with the error that prevented it; use Done to learn when the
+// forward ends. If the pod goes away, the forward moves to a ready pod from
+// the same controller.
+func (m *Manager) StartPortForward(namespace, podName, localPort, remotePort string) (*PortForward, error) {
+	conn := m.current()
+	if namespace == "" {
+		namespace = conn.namespace
+	}
+
+	pod, err := conn.clientset.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+
+	pf := newPortForward(conn, namespace, localPort, remotePort)
+	pf.resolve = podResolver(conn.clientset, namespace, pod, remotePort)
+	if err := m.startForward(pf, podName, remotePort); err != nil {
+		return nil, err
+	}
+	return pf, nil
+}
+
+// StartServicePortForward forwards localPort on localhost to a service port,
+// the way `kubectl port-forward svc/...` does: the service's selector and
+// targetPort are resolved to a ready pod. Unlike kubectl, the forward moves to
+// another ready pod when that one goes away, e.g. during a rollout.
+func (m *Manager) StartServicePortForward(namespace, serviceName, localPort, servicePort string) (*PortForward, error) {
+	conn := m.current()
+	if namespace == "" {
+		namespace = conn.namespace
+	}
+
+	pf := newPortForward(conn, namespace, localPort, servicePort)
+	pf.Service = serviceName
+	pf.resolve = func() (string, string, error) {
+		return resolveServicePod(conn.clientset, namespace, serviceName, servicePort)
+	}
+
+	podName, podPort, err := pf.resolve()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.startForward(pf, podName, podPort); err != nil {
+		return nil, err
+	}
+	return pf, nil
+}
+
+// StartSelectorPortForward forwards localPort on localhost to remotePort on
+// the newest ready pod matching a label selector. An empty namespace means
+// the current one. Reconnects pick a ready pod by the same selector, so the
+// forward follows replacements whatever created the pods.
+func (m *Manager) StartSelectorPortForward(namespace, selector, localPort, remotePort string) (*PortForward, error) {
+	conn := m.current()
+	if namespace == "" {
+		namespace = conn.namespace
+	}
+
+	pf := newPortForward(conn, namespace, localPort, remotePort)
+	pf.Selector = selector
+	pf.resolve = func() (string, string, error) {
+		pod, err := findReadyPod(conn.clientset, namespace, selector)
+		if err != nil {
+			return "", "", err
+		}
+		return pod.Name, remotePort, nil
+	}
+
+	podName, podPort, err := pf.resolve()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.startForward(pf, podName, podPort); err != nil {
+		return nil, err
+	}
+	return pf, nil
+}
+
+// StopPortForward stops an active port forward and waits for its local
+// listener to close
+func (m *Manager) StopPortForward(pf *PortForward) error {
+	if pf == nil {
+		return fmt.Errorf("invalid port forward")
+	}
+
+	pf.stop()
+	<-pf.doneCh
+	return nil
+}
+
+func newPortForward(conn connection, namespace, localPort, remotePort string) *PortForward {
+	return &PortForward{
+		conn:       conn,
+		Namespace:  namespace,
+		LocalPort:  localPort,
+		RemotePort: remotePort,
+		state:      PortForwardStarting,
+		events:     make(chan PortForwardEvent, portForwardEventBuffer),
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// startForward opens the first session and hands the forward to its supervisor
+func (m *Manager) startForward(pf *PortForward, podName, podPort string) error {
+	errCh, localPort, err := m.openSession(pf, podName, podPort)
+	if err != nil {
+		pf.stop()
+		return err
+	}
+
+	// Reconnects must reuse the port actually bound, which differs when
+	// localPort is "0"
+	pf.LocalPort = localPort
+	pf.mu.Lock()
+	pf.podName = podName
+	pf.state = PortForwardActive
+	pf.mu.Unlock()
+
+	go m.supervise(pf, errCh)
+	return nil
+}
+
+// openSession starts forwarding to one pod and waits until the local port is
+// listening. The returned channel yields the session's exit error.
+func (m *Manager) openSession(pf *PortForward, podName, podPort string) (<-chan error, string, error) {
+	dialer, err := newPortForwardDialer(pf.conn, pf.Namespace, podName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The session has its own stop channel, so one that never becomes ready
+	// can be torn down without stopping the forward
+	sessionStop := make(chan struct{})
+	var stopOnce sync.Once
+	stopSession := func() { stopOnce.Do(func() { close(sessionStop) }) }
+
+	readyCh := make(chan struct{})
+	fw, err := portforward.New(dialer, []string{pf.LocalPort + ":" + podPort}, sessionStop, readyCh, connectionCounter{pf}, io.Discard)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to set up port-forward to %s: %w", podName, err)
+	}
+
+	errCh := make(chan error, 1)
+	fwDone := make(chan struct{})
+	go func() {
+		errCh <- fw.ForwardPorts()
+		close(fwDone)
+	}()
+	go func() {
+		select {
+		case <-pf.stopCh:
+			stopSession()
+		case <-fwDone:
+		}
+	}()
+
+	select {
+	case <-readyCh:
+	case err := <-errCh:
+		return nil, "", fmt.Errorf("failed to start port-forward to %s: %w", podName, err)
+	case <-pf.stopCh:
+		<-fwDone
+		return nil, "", errors.New("port-forward stopped")
+	case <-time.After(portForwardTimeout):
+		// Left running, the session could still bind the local port and
+		// make the next attempt fail with "address already in use"
+		stopSession()
+		<-fwDone
+		return nil, "", fmt.Errorf("failed to start port-forward to %s: timed out waiting for connection", podName)
+	}
+
+	localPort := pf.LocalPort
+	if ports, err := fw.GetPorts(); err == nil && len(ports) > 0 {
+		localPort = strconv.Itoa(int(ports[0].Local))
+	}
+	return errCh, localPort, nil
+}
+
+// supervise waits for each session to end and reconnects until the forward
+// is stopped or reconnecting gives up
+func (m *Manager) supervise(pf *PortForward, errCh <-chan error) {
+	defer close(pf.doneCh)
+
+	for {
+		err := <-errCh
+		if pf.stopped() {
+			pf.setState(PortForwardStopped, nil)
+			return
+		}
+		if err == nil {
+			err = errors.New("port-forward ended unexpectedly")
+		}
+		pf.disconnected(err)
+
+		errCh, err = m.reconnect(pf)
+		if err != nil {
+			if pf.stopped() {
+				pf.setState(PortForwardStopped, nil)
+			} else {
+				pf.setState(PortForwardFailed, err)
+			}
+			return
+		}
+	}
+}
+
+// reconnect re-resolves the forward's pod and opens a new session, backing
+// off between attempts while e.g. a replacement pod becomes ready
+func (m *Manager) reconnect(pf *PortForward) (<-chan error, error) {
+	delay := reconnectBaseDelay
+	var err error
+	for attempt := 0; attempt < maxReconnectAttempts; attempt++ {
+		select {
+		case <-pf.stopCh:
+			return nil, errors.New("port-forward stopped")
+		case <-time.After(delay):
+		}
+
+		var podName, podPort string
+		podName, podPort, err = pf.resolve()
+		if err == nil {
+			var errCh <-chan error
+			errCh, _, err = m.openSession(pf, podName, podPort)
+			if err == nil {
+				pf.reconnected(podName)
+				return errCh, nil
+			}
+		}
+		pf.recordError(err)
+		delay = min(delay*2, reconnectMaxDelay)
+	}
+	return nil, fmt.Errorf("gave up after %d reconnect attempts: %w", maxReconnectAttempts, err)
+}
+
+// podResolver reconnects to the same pod while it is ready. Once it isn't,
+// a ready pod with the same labels is used instead, provided the pod belongs
+// to a controller that would replace it.
+func podResolver(clientset *kubernetes.Clientset, namespace string, pod *corev1.Pod, podPort string) func() (string, string, error) {
+	var selector string
+	if metav1.GetControllerOf(pod) != nil && len(pod.Labels) > 0 {
+		// The template hash changes with every rollout; leave it out so pods
+		// from the new ReplicaSet match too
+		set := labels.Set{}
+		for k, v := range pod.Labels {
+			if k != appsv1.DefaultDeploymentUniqueLabelKey {
+				set[k] = v
+			}
+		}
+		selector = labels.SelectorFromSet(set).String()
+	}
+
+	return func() (string, string, error) {
+		current, err := clientset.CoreV1().Pods(namespace).Get(context.Background(), pod.Name, metav1.GetOptions{})
+		switch {
+		case err == nil && isPodReady(current):
+			return pod.Name, podPort, nil
+		case err != nil && !apierrors.IsNotFound(err):
+			return "", "", fmt.Errorf("failed to get pod %s: %w", pod.Name, err)
+		case selector == "":
+			return "", "", fmt.Errorf("pod %s is not ready", pod.Name)
+		}
+
+		replacement, err := findReadyPod(clientset, namespace, selector)
+		if err != nil {
+			return "", "", err
+		}
+		return replacement.Name, podPort, nil
+	}
+}
+
+// resolveServicePod picks a ready pod behind a service and the pod port that
+// servicePort maps to
+func resolveServicePod(clientset *kubernetes.Clientset, namespace, serviceName, servicePort string) (string, string, error) {
+	svc, err := clientset.CoreV1().Services(namespace).Get(context.Background(), serviceName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get service %s: %w", serviceName, err)
+	}
+	if len(svc.Spec.Selector) == 0 {
+		return "", "", fmt.Errorf("service %s has no selector", serviceName)
+	}
+
+	var port *corev1.ServicePort
+	for i := range svc.Spec.Ports {
+		if strconv.Itoa(int(svc.Spec.Ports[i].Port)) == servicePort {
+			port = &svc.Spec.Ports[i]
+			break
+		}
+	}
+	if port == nil {
+		return "", "", fmt.Errorf("service %s has no port %s", serviceName, servicePort)
+	}
+
+	pod, err := findReadyPod(clientset, namespace, labels.SelectorFromSet(svc.Spec.Selector).String())
+	if err != nil {
+		return "", "", fmt.Errorf("service %s: %w", serviceName, err)
+	}
+
+	// Resolve targetPort as kubectl does: unset means the service port, and a
+	// name refers to a container port in the chosen pod
+	switch {
+	case port.TargetPort.Type == intstr.String:
+		for _, c := range pod.Spec.Containers {
+			for _, p := range c.Ports {
+				if p.Name == port.TargetPort.StrVal {
+					return pod.Name, strconv.Itoa(int(p.ContainerPort)), nil
+				}
+			}
+		}
+		return "", "", fmt.Errorf("pod %s has no port named %q", pod.Name, port.TargetPort.StrVal)
+	case port.TargetPort.IntVal != 0:
+		return pod.Name, strconv.Itoa(int(port.TargetPort.IntVal)), nil
+	default:
+		return pod.Name, servicePort, nil
+	}
+}
+
+// GetPodPorts returns the ports declared by a pod's containers
+func (m *Manager) GetPodPorts(podName string) ([]ContainerPort, error) {
+	clientset, namespace := m.client()
+	pod, err := clientset.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+
+	var ports []ContainerPort
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			protocol := string(p.Protocol)
+			if protocol == "" {
+				protocol = string(corev1.ProtocolTCP)
+			}
+			ports = append(ports, ContainerPort{
+				Container: c.Name,
+				Name:      p.Name,
+				Port:      p.ContainerPort,
+				Protocol:  protocol,
+			})
+		}
+	}
+	return ports, nil
+}
+
+// GetServicePorts returns the ports exposed by a service
+func (m *Manager) GetServicePorts(serviceName string) ([]ServicePort, error) {
+	clientset, namespace := m.client()
+	svc, err := clientset.CoreV1().Services(namespace).Get(context.Background(), serviceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s: %w", serviceName, err)
+	}
+
+	ports := make([]ServicePort, 0, len(svc.Spec.Ports))
+	for _, p := range svc.Spec.Ports {
+		protocol := string(p.Protocol)
+		if protocol == "" {
+			protocol = string(corev1.ProtocolTCP)
+		}
+		target := p.TargetPort.String()
+		if p.TargetPort.Type == intstr.Int && p.TargetPort.IntVal == 0 {
+			target = strconv.Itoa(int(p.Port))
+		}
+		ports = append(ports, ServicePort{
+			Name:       p.Name,
+			Port:       p.Port,
+			TargetPort: target,
+			Protocol:   protocol,
+		})
+	}
+	return ports, nil
+}
+
+// FreeLocalPort returns preferred if it can be bound on localhost, or else a
+// free port chosen by the OS, e.g. when preferred is taken or privileged
+func FreeLocalPort(preferred int) (int, error) {
+	if l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(preferred))); err == nil {
+		l.Close()
+		return preferred, nil
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free local port: %w", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// findReadyPod returns the newest ready pod matching a selector; older ones
+// are the likeliest to be replaced
+func findReadyPod(clientset *kubernetes.Clientset, namespace, selector string) (*corev1.Pod, error) {
+	pods, err := clientset.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods matching %q: %w", selector, err)
+	}
+
+	var found *corev1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !isPodReady(pod) {
+			continue
+		}
+		if found == nil || pod.CreationTimestamp.After(found.CreationTimestamp.Time) {
+			found = pod
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no ready pod in %s matches %q", namespace, selector)
+	}
+	return found, nil
+}
+
+// isPodReady reports whether a pod is running, not being deleted, and passing
+// its readiness checks
+func isPodReady(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// newPortForwardDialer builds a dialer that prefers SPDY tunneled over
+// WebSockets and falls back to plain SPDY, as kubectl does
+func newPortForwardDialer(conn connection, namespace, podName string) (httpstream.Dialer, error) {
+	req := conn.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(conn.restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SPDY transport: %w", err)
+	}
+	spdyDialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	wsDialer, err := portforward.NewSPDYOverWebsocketDialer(req.URL(), conn.restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create WebSocket dialer: %w", err)
+	}
+
+	return portforward.NewFallbackDialer(wsDialer, spdyDialer, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	}), nil
+}
@@ -0,0 +1,192 @@
+package systemd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// JournalEntry represents a single journal record
+type JournalEntry struct {
+	Time       time.Time
+	Priority   int // 0 (emerg) to 7 (debug), -1 if unknown
+	Unit       string
+	Identifier string // SYSLOG_IDENTIFIER, e.g. "nginx"
+	PID        string
+	BootID     string
+	Message    string
+}
+
+// JournalOptions controls which journal entries are read
+type JournalOptions struct {
+	Unit     string
+	Lines    int    // number of most recent entries to return, 0 for all
+	Priority string // passed to journalctl -p, e.g. "err" or "0..4"; empty for all
+	Boot     string // passed to journalctl -b, e.g. "0" or "-1"; empty for all boots
+}
+
+// String formats the entry like journalctl's default short output
+func (e JournalEntry) String() string {
+	source := e.Identifier
+	if e.PID != "" {
+		source += "[" + e.PID + "]"
+	}
+	return fmt.Sprintf("%s %s: %s", e.Time.Format(time.Stamp), source, e.Message)
+}
+
+// GetUnitJournal returns the most recent journal entries for a unit
+func (m *Manager) GetUnitJournal(opts JournalOptions) ([]JournalEntry, error) {
+	out, err := exec.Command("journalctl", journalArgs(opts, false)...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("failed to read journal for %s: %s", opts.Unit, exitErr.Stderr)
+		}
+		return nil, fmt.Errorf("failed to read journal for %s: %w", opts.Unit, err)
+	}
+
+	return ParseJournal(bytes.NewReader(out))
+}
+
+// FollowUnitJournal streams journal entries for a unit until ctx is cancelled.
+// The entries channel is closed when the stream ends.
+func (m *Manager) FollowUnitJournal(ctx context.Context, opts JournalOptions) (<-chan JournalEntry, error) {
+	cmd := exec.CommandContext(ctx, "journalctl", journalArgs(opts, true)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow journal for %s: %w", opts.Unit, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to follow journal for %s: %w", opts.Unit, err)
+	}
+
+	entries := make(chan JournalEntry, 64)
+	go func() {
+		defer close(entries)
+		defer cmd.Wait()
+
+		scanner := newJournalScanner(stdout)
+		for scanner.Scan() {
+			entry, err := ParseJournalEntry(scanner.Bytes())
+			if err != nil {
+				continue
+			}
+			select {
+			case entries <- entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return entries, nil
+}
+
+// ParseJournal parses `journalctl -o json` output, one JSON object per line
+func ParseJournal(r io.Reader) ([]JournalEntry, error) {
+	var entries []JournalEntry
+
+	scanner := newJournalScanner(r)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		entry, err := ParseJournalEntry(line)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal output: %w", err)
+	}
+
+	return entries, nil
+}
+
+// ParseJournalEntry parses a single `journalctl -o json` record
+func ParseJournalEntry(line []byte) (JournalEntry, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return JournalEntry{}, fmt.Errorf("failed to parse journal entry: %w", err)
+	}
+
+	entry := JournalEntry{
+		Priority:   -1,
+		Unit:       journalField(fields, "_SYSTEMD_UNIT"),
+		Identifier: journalField(fields, "SYSLOG_IDENTIFIER"),
+		PID:        journalField(fields, "_PID"),
+		BootID:     journalField(fields, "_BOOT_ID"),
+		Message:    journalField(fields, "MESSAGE"),
+	}
+
+	if usec, err := strconv.ParseInt(journalField(fields, "__REALTIME_TIMESTAMP"), 10, 64); err == nil {
+		entry.Time = time.UnixMicro(usec)
+	}
+	if prio, err := strconv.Atoi(journalField(fields, "PRIORITY")); err == nil {
+		entry.Priority = prio
+	}
+	if entry.Identifier == "" {
+		entry.Identifier = journalField(fields, "_COMM")
+	}
+
+	return entry, nil
+}
+
+// journalField decodes a journal field. journalctl emits plain strings, but
+// encodes non-UTF-8 values as byte arrays and unset fields as null.
+func journalField(fields map[string]json.RawMessage, key string) string {
+	raw, ok := fields[key]
+	if !ok {
+		return ""
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var ints []int
+	if err := json.Unmarshal(raw, &ints); err == nil {
+		b := make([]byte, len(ints))
+		for i, v := range ints {
+			b[i] = byte(v)
+		}
+		return string(b)
+	}
+
+	return ""
+}
+
+// journalArgs builds the journalctl arguments for the given options
+func journalArgs(opts JournalOptions, follow bool) []string {
+	args := []string{"-u", opts.Unit, "-o", "json", "--no-pager"}
+	if opts.Lines > 0 {
+		args = append(args, "-n", strconv.Itoa(opts.Lines))
+	} else if follow {
+		args = append(args, "-n", "0")
+	}
+	if opts.Priority != "" {
+		args = append(args, "-p", opts.Priority)
+	}
+	if opts.Boot != "" {
+		args = append(args, "-b", opts.Boot)
+	}
+	if follow {
+		args = append(args, "-f")
+	}
+	return args
+}
+
+// newJournalScanner returns a line scanner sized for long journal messages
+func newJournalScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return scanner
+}
@@ -0,0 +1,194 @@
+package systemd
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseJournal(t *testing.T) {
+	f, err := os.Open("testdata/nginx.journal.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	entries, err := ParseJournal(f)
+	if err != nil {
+		t.Fatalf("ParseJournal: %v", err)
+	}
+
+	const boot = "9f2d4c6e8a0b4c1d9e7f5a3b1c2d4e6f"
+	want := []JournalEntry{
+		{
+			Time:       time.UnixMicro(1735732800000000),
+			Priority:   6,
+			Unit:       "init.scope",
+			Identifier: "systemd",
+			PID:        "1",
+			BootID:     boot,
+			Message:    "Starting A high performance web server and a reverse proxy server...",
+		},
+		{
+			Time:       time.UnixMicro(1735732800250000),
+			Priority:   3,
+			Unit:       "nginx.service",
+			Identifier: "nginx",
+			PID:        "4312",
+			BootID:     boot,
+			Message:    "nginx: [emerg] bind() to 0.0.0.0:80 failed (98: Address already in use)",
+		},
+		{
+			// MESSAGE is a byte array because it isn't valid UTF-8, and
+			// without SYSLOG_IDENTIFIER the command name is used
+			Time:       time.UnixMicro(1735732800311000),
+			Priority:   4,
+			Unit:       "nginx.service",
+			Identifier: "nginx",
+			PID:        "4312",
+			BootID:     boot,
+			Message:    "upstream \xff\xfe sent bad bytes",
+		},
+		{
+			// No PRIORITY
+			Time:       time.UnixMicro(1735732800372000),
+			Priority:   -1,
+			Unit:       "nginx.service",
+			Identifier: "nginx",
+			PID:        "4313",
+			BootID:     boot,
+			Message:    "worker process started",
+		},
+		{
+			// Null fields decode as empty
+			Time:       time.UnixMicro(1735732800433000),
+			Priority:   6,
+			Unit:       "nginx.service",
+			Identifier: "nginx",
+			BootID:     boot,
+		},
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i := range want {
+		if !entries[i].Time.Equal(want[i].Time) {
+			t.Errorf("entry %d: Time = %v, want %v", i, entries[i].Time, want[i].Time)
+		}
+		got, w := entries[i], want[i]
+		got.Time, w.Time = time.Time{}, time.Time{}
+		if got != w {
+			t.Errorf("entry %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestParseJournalEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    JournalEntry
+		wantErr bool
+	}{
+		{
+			name: "string message",
+			line: `{"PRIORITY":"5","SYSLOG_IDENTIFIER":"sshd","_PID":"88","MESSAGE":"Accepted publickey"}`,
+			want: JournalEntry{Priority: 5, Identifier: "sshd", PID: "88", Message: "Accepted publickey"},
+		},
+		{
+			name: "byte array message",
+			line: `{"PRIORITY":"6","MESSAGE":[104,105,10,255]}`,
+			want: JournalEntry{Priority: 6, Message: "hi\n\xff"},
+		},
+		{
+			name: "missing priority",
+			line: `{"MESSAGE":"no priority"}`,
+			want: JournalEntry{Priority: -1, Message: "no priority"},
+		},
+		{
+			name: "unparsable priority",
+			line: `{"PRIORITY":"high","MESSAGE":"x"}`,
+			want: JournalEntry{Priority: -1, Message: "x"},
+		},
+		{
+			name: "identifier falls back to comm",
+			line: `{"_COMM":"cron","MESSAGE":"x"}`,
+			want: JournalEntry{Priority: -1, Identifier: "cron", Message: "x"},
+		},
+		{
+			name:    "not json",
+			line:    `-- No entries --`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJournalEntry([]byte(tt.line))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("ParseJournalEntry = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJournalArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   JournalOptions
+		follow bool
+		want   []string
+	}{
+		{
+			name: "all entries",
+			opts: JournalOptions{Unit: "nginx.service"},
+			want: []string{"-u", "nginx.service", "-o", "json", "--no-pager"},
+		},
+		{
+			name: "tail",
+			opts: JournalOptions{Unit: "nginx.service", Lines: 200},
+			want: []string{"-u", "nginx.service", "-o", "json", "--no-pager", "-n", "200"},
+		},
+		{
+			name:   "follow from now",
+			opts:   JournalOptions{Unit: "nginx.service"},
+			follow: true,
+			want:   []string{"-u", "nginx.service", "-o", "json", "--no-pager", "-n", "0", "-f"},
+		},
+		{
+			name:   "follow with tail",
+			opts:   JournalOptions{Unit: "nginx.service", Lines: 50},
+			follow: true,
+			want:   []string{"-u", "nginx.service", "-o", "json", "--no-pager", "-n", "50", "-f"},
+		},
+		{
+			name: "priority",
+			opts: JournalOptions{Unit: "nginx.service", Priority: "0..3"},
+			want: []string{"-u", "nginx.service", "-o", "json", "--no-pager", "-p", "0..3"},
+		},
+		{
+			name: "previous boot",
+			opts: JournalOptions{Unit: "nginx.service", Boot: "-1"},
+			want: []string{"-u", "nginx.service", "-o", "json", "--no-pager", "-b", "-1"},
+		},
+		{
+			name:   "everything",
+			opts:   JournalOptions{Unit: "sshd.service", Lines: 10, Priority: "err", Boot: "0"},
+			follow: true,
+			want:   []string{"-u", "sshd.service", "-o", "json", "--no-pager", "-n", "10", "-p", "err", "-b", "0", "-f"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := journalArgs(tt.opts, tt.follow); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("journalArgs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+)
+
+// jobTimeout bounds how long we wait for systemd to finish a queued job.
+// A variable so tests can shorten it.
+var jobTimeout = 30 * time.Second
+
+// UnitInfo represents a systemd unit
+type UnitInfo struct {
+	Name        string
+	Description string
+	LoadState   string // loaded, not-found, masked, ...
+	ActiveState string // active, inactive, failed, activating, deactivating
+	SubState    string // running, exited, dead, ...
+}
+
+// UnitStatus represents the detailed status of a single unit
+type UnitStatus struct {
+	UnitInfo
+	UnitFileState string // enabled, disabled, static, ...
+	FragmentPath  string
+	MainPID       uint32
+	ActiveSince   time.Time
+}
+
+// UnitUsage is a unit's resource usage from its cgroup accounting. A field
+// is 0 when systemd doesn't account for it (e.g. accounting is disabled).
+type UnitUsage struct {
+	CPUNanos    uint64 // cumulative CPU time
+	MemoryBytes uint64
+	MemoryMax   uint64 // 0 when unlimited
+}
+
+// cgroupUnitTypes maps unit name suffixes to the D-Bus interface holding
+// their cgroup properties
+var cgroupUnitTypes = map[string]string{
+	".service": "Service",
+	".scope":   "Scope",
+	".slice":   "Slice",
+	".socket":  "Socket",
+	".mount":   "Mount",
+	".swap":    "Swap",
+}
+
+// Bus is the subset of the systemd D-Bus API used by Manager.
+// *dbus.Conn satisfies it; tests can substitute an in-memory fake.
+type Bus interface {
+	ListUnitsContext(ctx context.Context) ([]dbus.UnitStatus, error)
+	ListUnitsByNamesContext(ctx context.Context, units []string) ([]dbus.UnitStatus, error)
+	GetUnitPropertiesContext(ctx context.Context, unit string) (map[string]interface{}, error)
+	GetUnitTypePropertiesContext(ctx context.Context, unit string, unitType string) (map[string]interface{}, error)
+	StartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	StopUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	RestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	ReloadUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+	Subscribe() error
+	Unsubscribe() error
+	SetSubStateSubscriber(updateCh chan<- *dbus.SubStateUpdate, errCh chan<- error)
+	Close()
+}
+
+var _ Bus = (*dbus.Conn)(nil)
+
+// UnitEvent is delivered by WatchUnits when a unit's state changes.
+// Err is set instead of Unit when the subscription reports a problem.
+type UnitEvent struct {
+	Unit UnitInfo
+	Err  error
+}
+
+// subscriptionBuffer sizes the channels handed to go-systemd. It drops
+// updates when they are full, so keep them roomy for bursts of changes.
+const subscriptionBuffer = 256
+
+// jobFunc is the shape shared by the Start/Stop/Restart/Reload bus methods
+type jobFunc func(ctx context.Context, name string, mode string, ch chan<- string) (int, error)
+
+// Manager handles systemd interactions over D-Bus
+type Manager struct {
+	bus Bus
+}
+
+// NewManager connects to the system bus and returns a Manager
+func NewManager() (*Manager, error) {
+	conn, err := dbus.NewSystemConnectionContext(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to systemd D-Bus: %w", err)
+	}
+
+	return NewManagerWithBus(conn), nil
+}
+
+// NewManagerWithBus returns a Manager backed by the given bus
+func NewManagerWithBus(bus Bus) *Manager {
+	return &Manager{bus: bus}
+}
+
+// Close closes the underlying D-Bus connection
+func (m *Manager) Close() {
+	if m.bus != nil {
+		m.bus.Close()
+	}
+}
+
+// ListUnits returns all units currently loaded by systemd, sorted by name
+func (m *Manager) ListUnits() ([]UnitInfo, error) {
+	units, err := m.bus.ListUnitsContext(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units: %w", err)
+	}
+
+	return toUnitInfos(units), nil
+}
+
+// ListUnitsByNames returns the named units, including ones that are not loaded
+func (m *Manager) ListUnitsByNames(names []string) ([]UnitInfo, error) {
+	if len(names) == 0 {
+		return []UnitInfo{}, nil
+	}
+
+	units, err := m.bus.ListUnitsByNamesContext(context.Background(), names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list units %s: %w", strings.Join(names, ", "), err)
+	}
+
+	return toUnitInfos(units), nil
+}
+
+// GetUnitStatus returns detailed status for a specific unit
+func (m *Manager) GetUnitStatus(name string) (*UnitStatus, error) {
+	props, err := m.bus.GetUnitPropertiesContext(context.Background(), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status for unit %s: %w", name, err)
+	}
+
+	status := &UnitStatus{
+		UnitInfo: UnitInfo{
+			Name:        name,
+			Description: stringProp(props, "Description"),
+			LoadState:   stringProp(props, "LoadState"),
+			ActiveState: stringProp(props, "ActiveState"),
+			SubState:    stringProp(props, "SubState"),
+		},
+		UnitFileState: stringProp(props, "UnitFileState"),
+		FragmentPath:  stringProp(props, "FragmentPath"),
+	}
+
+	// ActiveEnterTimestamp is in microseconds since the epoch, 0 if never active
+	if usec, ok := props["ActiveEnterTimestamp"].(uint64); ok && usec > 0 {
+		status.ActiveSince = time.UnixMicro(int64(usec))
+	}
+
+	// MainPID only exists on service units
+	if strings.HasSuffix(name, ".service") {
+		serviceProps, err := m.bus.GetUnitTypePropertiesContext(context.Background(), name, "Service")
+		if err == nil {
+			if pid, ok := serviceProps["MainPID"].(uint32); ok {
+				status.MainPID = pid
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// GetUnitUsage returns the CPU and memory accounted to a unit's cgroup
+func (m *Manager) GetUnitUsage(name string) (*UnitUsage, error) {
+	unitType, ok := cgroupUnitTypes[filepath.Ext(name)]
+	if !ok {
+		return nil, fmt.Errorf("unit %s has no cgroup", name)
+	}
+
+	props, err := m.bus.GetUnitTypePropertiesContext(context.Background(), name, unitType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage for unit %s: %w", name, err)
+	}
+
+	return &UnitUsage{
+		CPUNanos:    accountedProp(props, "CPUUsageNSec"),
+		MemoryBytes: accountedProp(props, "MemoryCurrent"),
+		MemoryMax:   accountedProp(props, "MemoryMax"),
+	}, nil
+}
+
+// StartUnit starts a unit and waits for the job to complete
+func (m *Manager) StartUnit(name string) error {
+	return m.runJob("start", name, m.bus.StartUnitContext)
+}
+
+// StopUnit stops a unit and waits for the job to complete
+func (m *Manager) StopUnit(name string) error {
+	return m.runJob("stop", name, m.bus.StopUnitContext)
+}
+
+// RestartUnit restarts a unit and waits for the job to complete
+func (m *Manager) RestartUnit(name string) error {
+	return m.runJob("restart", name, m.bus.RestartUnitContext)
+}
+
+// ReloadUnit reloads a unit's configuration and waits for the job to complete
+func (m *Manager) ReloadUnit(name string) error {
+	return m.runJob("reload", name, m.bus.ReloadUnitContext)
+}
+
+// WatchUnits streams state changes for units accepted by filter until ctx is
+// cancelled. It relies on systemd's PropertiesChanged signals rather than
+// SubscribeUnitsCustom, which re-lists every unit on each poll interval.
+func (m *Manager) WatchUnits(ctx context.Context, filter func(name string) bool) (<-chan UnitEvent, error) {
+	if err := m.bus.Subscribe(); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to unit changes: %w", err)
+	}
+
+	updateCh := make(chan *dbus.SubStateUpdate, subscriptionBuffer)
+	errCh := make(chan error, subscriptionBuffer)
+	m.bus.SetSubStateSubscriber(updateCh, errCh)
+
+	events := make(chan UnitEvent, subscriptionBuffer)
+	go func() {
+		defer close(events)
+		defer m.bus.Unsubscribe()
+		defer m.bus.SetSubStateSubscriber(nil, nil)
+
+		for {
+			var event UnitEvent
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-errCh:
+				event.Err = err
+			case update := <-updateCh:
+				if !filter(update.UnitName) {
+					continue
+				}
+				// The update only carries the sub state; fetch the rest
+				units, err := m.bus.ListUnitsByNamesContext(ctx, []string{update.UnitName})
+				if err != nil {
+					event.Err = fmt.Errorf("failed to get unit %s: %w", update.UnitName, err)
+				} else if len(units) == 0 {
+					continue
+				} else {
+					event.Unit = toUnitInfos(units)[0]
+				}
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// runJob queues a systemd job and blocks until systemd reports its result
+func (m *Manager) runJob(action, name string, fn jobFunc) error {
+	ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
+	defer cancel()
+
+	// Buffered so a late job result never blocks the D-Bus dispatcher
+	resultCh := make(chan string, 1)
+	if _, err := fn(ctx, name, "replace", resultCh); err != nil {
+		return fmt.Errorf("failed to %s unit %s: %w", action, name, err)
+	}
+
+	select {
+	case result := <-resultCh:
+		if result != "done" {
+			return fmt.Errorf("failed to %s unit %s: job %s", action, name, result)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to %s unit %s: timed out waiting for job", action, name)
+	}
+}
+
+// toUnitInfos converts D-Bus unit statuses into UnitInfos sorted by name
+func toUnitInfos(units []dbus.UnitStatus) []UnitInfo {
+	infos := make([]UnitInfo, 0, len(units))
+	for _, u := range units {
+		infos = append(infos, UnitInfo{
+			Name:        u.Name,
+			Description: u.Description,
+			LoadState:   u.LoadState,
+			ActiveState: u.ActiveState,
+			SubState:    u.SubState,
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+
+	return infos
+}
+
+// accountedProp returns a uint64 accounting property, or 0 if it is missing
+// or unset, which systemd reports as the maximum uint64
+func accountedProp(props map[string]interface{}, key string) uint64 {
+	if v, ok := props[key].(uint64); ok && v != math.MaxUint64 {
+		return v
+	}
+	return 0
+}
+
+// stringProp returns a string property, or "" if it is missing or not a string
+func stringProp(props map[string]interface{}, key string) string {
+	if v, ok := props[key].(string); ok {
+		return v
+	}
+	return ""
+}
@@ -0,0 +1,298 @@
+package systemd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/dbus"
+)
+
+// fakeBus is an in-memory Bus. Jobs report jobResult on their channel, or
+// nothing when it is empty, so a job can be left hanging.
+type fakeBus struct {
+	units     []dbus.UnitStatus
+	listErr   error
+	jobResult string
+	jobErr    error
+
+	mu          sync.Mutex
+	jobs        []string // "<action> <unit> <mode>"
+	listedNames [][]string
+	subscribed  bool
+	updateCh    chan<- *dbus.SubStateUpdate
+	errCh       chan<- error
+	closed      bool
+}
+
+var _ Bus = (*fakeBus)(nil)
+
+func (b *fakeBus) ListUnitsContext(ctx context.Context) ([]dbus.UnitStatus, error) {
+	if b.listErr != nil {
+		return nil, b.listErr
+	}
+	return append([]dbus.UnitStatus(nil), b.units...), nil
+}
+
+func (b *fakeBus) ListUnitsByNamesContext(ctx context.Context, names []string) ([]dbus.UnitStatus, error) {
+	b.mu.Lock()
+	b.listedNames = append(b.listedNames, names)
+	b.mu.Unlock()
+
+	if b.listErr != nil {
+		return nil, b.listErr
+	}
+	var units []dbus.UnitStatus
+	for _, name := range names {
+		for _, u := range b.units {
+			if u.Name == name {
+				units = append(units, u)
+			}
+		}
+	}
+	return units, nil
+}
+
+func (b *fakeBus) GetUnitPropertiesContext(ctx context.Context, unit string) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func (b *fakeBus) GetUnitTypePropertiesContext(ctx context.Context, unit string, unitType string) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+
+func (b *fakeBus) job(action, name, mode string, ch chan<- string) (int, error) {
+	b.mu.Lock()
+	b.jobs = append(b.jobs, action+" "+name+" "+mode)
+	b.mu.Unlock()
+
+	if b.jobErr != nil {
+		return 0, b.jobErr
+	}
+	if b.jobResult != "" {
+		ch <- b.jobResult
+	}
+	return 1, nil
+}
+
+func (b *fakeBus) StartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	return b.job("start", name, mode, ch)
+}
+
+func (b *fakeBus) StopUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	return b.job("stop", name, mode, ch)
+}
+
+func (b *fakeBus) RestartUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	return b.job("restart", name, mode, ch)
+}
+
+func (b *fakeBus) ReloadUnitContext(ctx context.Context, name string, mode string, ch chan<- string) (int, error) {
+	return b.job("reload", name, mode, ch)
+}
+
+func (b *fakeBus) Subscribe() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribed = true
+	return nil
+}
+
+func (b *fakeBus) Unsubscribe() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribed = false
+	return nil
+}
+
+func (b *fakeBus) SetSubStateSubscriber(updateCh chan<- *dbus.SubStateUpdate, errCh chan<- error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.updateCh, b.errCh = updateCh, errCh
+}
+
+func (b *fakeBus) Close() {
+	b.closed = true
+}
+
+// subscriber returns the channels WatchUnits handed to the bus
+func (b *fakeBus) subscriber() (chan<- *dbus.SubStateUpdate, chan<- error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.updateCh, b.errCh
+}
+
+func TestListUnits(t *testing.T) {
+	bus := &fakeBus{units: []dbus.UnitStatus{
+		{Name: "nginx.service", Description: "web", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+		{Name: "cron.service", LoadState: "loaded", ActiveState: "inactive", SubState: "dead"},
+		{Name: "dbus.socket", LoadState: "loaded", ActiveState: "active", SubState: "listening"},
+	}}
+	m := NewManagerWithBus(bus)
+
+	units, err := m.ListUnits()
+	if err != nil {
+		t.Fatalf("ListUnits: %v", err)
+	}
+	want := []UnitInfo{
+		{Name: "cron.service", LoadState: "loaded", ActiveState: "inactive", SubState: "dead"},
+		{Name: "dbus.socket", LoadState: "loaded", ActiveState: "active", SubState: "listening"},
+		{Name: "nginx.service", Description: "web", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+	}
+	if !reflect.DeepEqual(units, want) {
+		t.Errorf("ListUnits = %+v, want %+v", units, want)
+	}
+
+	bus.listErr = errors.New("bus gone")
+	if _, err := m.ListUnits(); err == nil || !strings.Contains(err.Error(), "bus gone") {
+		t.Errorf("ListUnits error = %v, want it to wrap the bus error", err)
+	}
+}
+
+func TestListUnitsByNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		names     []string
+		want      []string
+		wantQuery bool
+	}{
+		{name: "no names", names: nil, want: []string{}, wantQuery: false},
+		{name: "subset", names: []string{"sshd.service", "nginx.service"}, want: []string{"nginx.service", "sshd.service"}, wantQuery: true},
+		{name: "unknown", names: []string{"missing.service"}, want: []string{}, wantQuery: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := &fakeBus{units: []dbus.UnitStatus{
+				{Name: "cron.service"},
+				{Name: "nginx.service"},
+				{Name: "sshd.service"},
+			}}
+			m := NewManagerWithBus(bus)
+
+			units, err := m.ListUnitsByNames(tt.names)
+			if err != nil {
+				t.Fatalf("ListUnitsByNames: %v", err)
+			}
+			got := []string{}
+			for _, u := range units {
+				got = append(got, u.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListUnitsByNames(%v) = %v, want %v", tt.names, got, tt.want)
+			}
+			if queried := len(bus.listedNames) > 0; queried != tt.wantQuery {
+				t.Errorf("queried the bus = %v, want %v", queried, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestRunJob(t *testing.T) {
+	defer func(d time.Duration) { jobTimeout = d }(jobTimeout)
+	jobTimeout = 50 * time.Millisecond
+
+	tests := []struct {
+		name      string
+		jobResult string
+		jobErr    error
+		run       func(*Manager) error
+		wantJob   string
+		wantErr   string // substring; empty for success
+	}{
+		{name: "start done", jobResult: "done", run: func(m *Manager) error { return m.StartUnit("nginx.service") }, wantJob: "start nginx.service replace"},
+		{name: "stop done", jobResult: "done", run: func(m *Manager) error { return m.StopUnit("nginx.service") }, wantJob: "stop nginx.service replace"},
+		{name: "restart failed", jobResult: "failed", run: func(m *Manager) error { return m.RestartUnit("nginx.service") }, wantJob: "restart nginx.service replace", wantErr: "failed to restart unit nginx.service: job failed"},
+		{name: "reload dependency", jobResult: "dependency", run: func(m *Manager) error { return m.ReloadUnit("nginx.service") }, wantJob: "reload nginx.service replace", wantErr: "job dependency"},
+		{name: "timeout", jobResult: "", run: func(m *Manager) error { return m.StartUnit("slow.service") }, wantJob: "start slow.service replace", wantErr: "timed out waiting for job"},
+		{name: "queue error", jobErr: errors.New("access denied"), run: func(m *Manager) error { return m.StopUnit("nginx.service") }, wantJob: "stop nginx.service replace", wantErr: "access denied"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := &fakeBus{jobResult: tt.jobResult, jobErr: tt.jobErr}
+			err := tt.run(NewManagerWithBus(bus))
+
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("error = %v, want it to contain %q", err, tt.wantErr)
+			}
+			if len(bus.jobs) != 1 || bus.jobs[0] != tt.wantJob {
+				t.Errorf("jobs = %v, want [%s]", bus.jobs, tt.wantJob)
+			}
+		})
+	}
+}
+
+func TestWatchUnits(t *testing.T) {
+	bus := &fakeBus{units: []dbus.UnitStatus{
+		{Name: "nginx.service", ActiveState: "active", SubState: "running"},
+		{Name: "cron.service", ActiveState: "active", SubState: "running"},
+	}}
+	m := NewManagerWithBus(bus)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := m.WatchUnits(ctx, func(name string) bool { return name == "nginx.service" })
+	if err != nil {
+		t.Fatalf("WatchUnits: %v", err)
+	}
+	bus.mu.Lock()
+	subscribed := bus.subscribed
+	bus.mu.Unlock()
+	if !subscribed {
+		t.Fatal("WatchUnits didn't subscribe to the bus")
+	}
+
+	updateCh, errCh := bus.subscriber()
+	next := func() UnitEvent {
+		t.Helper()
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for an event")
+			return UnitEvent{}
+		}
+	}
+
+	updateCh <- &dbus.SubStateUpdate{UnitName: "cron.service", SubState: "dead"}
+	updateCh <- &dbus.SubStateUpdate{UnitName: "nginx.service", SubState: "running"}
+	want := UnitInfo{Name: "nginx.service", ActiveState: "active", SubState: "running"}
+	if got := next(); got.Err != nil || got.Unit != want {
+		t.Errorf("event = %+v, want unit %+v", got, want)
+	}
+
+	errCh <- errors.New("signal lost")
+	if got := next(); got.Err == nil || got.Err.Error() != "signal lost" {
+		t.Errorf("event = %+v, want the subscription error", got)
+	}
+
+	// The filtered-out unit must not have been looked up
+	bus.mu.Lock()
+	for _, names := range bus.listedNames {
+		if len(names) != 1 || names[0] != "nginx.service" {
+			t.Errorf("looked up %v, want only nginx.service", names)
+		}
+	}
+	bus.mu.Unlock()
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("got an event after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("events channel not closed after cancel")
+	}
+	if updateCh, _ := bus.subscriber(); updateCh != nil {
+		t.Error("subscriber still set after cancel")
+	}
+}
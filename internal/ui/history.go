@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"time"
+)
+
+// historySamples is how many samples are kept per pod and unit
+const historySamples = 60
+
+// sparkBlocks are the levels of a sparkline, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sampleRing is a fixed-size ring of the most recent samples
+type sampleRing struct {
+	samples [historySamples]int64
+	next    int
+	count   int
+}
+
+// Add records a sample, overwriting the oldest once the ring is full
+func (r *sampleRing) Add(v int64) {
+	r.samples[r.next] = v
+	r.next = (r.next + 1) % historySamples
+	if r.count < historySamples {
+		r.count++
+	}
+}
+
+// Values returns the samples, oldest first
+func (r *sampleRing) Values() []int64 {
+	values := make([]int64, 0, r.count)
+	start := (r.next - r.count + historySamples) % historySamples
+	for i := 0; i < r.count; i++ {
+		values = append(values, r.samples[(start+i)%historySamples])
+	}
+	return values
+}
+
+// usageHistory is the CPU (millicores) and memory (bytes) history of a pod
+// or unit
+type usageHistory struct {
+	cpu sampleRing
+	mem sampleRing
+
+	// Units report cumulative CPU time; the rate comes from the last sample
+	lastCPUNanos uint64
+	lastAt       time.Time
+	memLimit     int64 // a unit's latest MemoryMax, 0 for none
+}
+
+// historyKey identifies a pod or unit in Model.history
+func historyKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// sparkline renders the last width values as a sparkline scaled to ceiling,
+// or to the largest value when ceiling is 0. It is left-padded to width.
+func sparkline(values []int64, ceiling int64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if ceiling <= 0 {
+		for _, v := range values {
+			ceiling = max(ceiling, v)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	top := int64(len(sparkBlocks) - 1)
+	for _, v := range values {
+		level := int64(0)
+		if ceiling > 0 {
+			level = min(max(v*top/ceiling, 0), top)
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Well-known keys of structured log lines, in order of preference
+var (
+	timeKeys    = []string{"time", "ts", "timestamp", "@timestamp", "t"}
+	levelKeys   = []string{"level", "lvl", "severity", "log.level", "@level"}
+	messageKeys = []string{"msg", "message", "@message"}
+)
+
+// structuredLog is a JSON or logfmt log line split into its parts
+type structuredLog struct {
+	time    string
+	level   string
+	message string
+	fields  []string // "key=value", in the configured order or sorted by key
+}
+
+// parseStructured parses a JSON or logfmt line, including one prefixed with
+// the timestamp Kubernetes adds when timestamps are requested. fields picks
+// the extra fields shown after the message; empty shows them all.
+func parseStructured(text string, fields []string) *structuredLog {
+	var prefixTime string
+	if ts, rest, ok := strings.Cut(text, " "); ok {
+		if _, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			prefixTime, text = ts, rest
+		}
+	}
+
+	values, ok := parseJSONLog(text)
+	if !ok {
+		values, ok = parseLogfmt(text)
+	}
+	if !ok {
+		return nil
+	}
+
+	s := &structuredLog{
+		time:    formatLogTime(takeField(values, timeKeys)),
+		level:   takeField(values, levelKeys),
+		message: takeField(values, messageKeys),
+	}
+	if s.time == "" && prefixTime != "" {
+		s.time = formatLogTime(prefixTime)
+	}
+
+	keys := fields
+	if len(keys) == 0 {
+		keys = make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+	}
+	for _, k := range keys {
+		if v, ok := values[k]; ok {
+			s.fields = append(s.fields, k+"="+v)
+		}
+	}
+	return s
+}
+
+// parseJSONLog flattens a JSON object line into string values
+func parseJSONLog(text string) (map[string]string, bool) {
+	if !strings.HasPrefix(strings.TrimSpace(text), "{") {
+		return nil, false
+	}
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(text), &obj); err != nil {
+		return nil, false
+	}
+
+	values := make(map[string]string, len(obj))
+	for k, v := range obj {
+		switch v := v.(type) {
+		case string:
+			values[k] = v
+		case float64:
+			values[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			b, _ := json.Marshal(v)
+			values[k] = string(b)
+		}
+	}
+	return values, true
+}
+
+// parseLogfmt parses a line of key=value pairs. Lines without a level or
+// message key aren't treated as logfmt, since plain text often has a few
+// "=" in it too.
+func parseLogfmt(text string) (map[string]string, bool) {
+	values := make(map[string]string)
+	rest := strings.TrimSpace(text)
+	for rest != "" {
+		key, after, ok := strings.Cut(rest, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t\"") {
+			return nil, false
+		}
+
+		var value string
+		if strings.HasPrefix(after, `"`) {
+			quoted, err := strconv.QuotedPrefix(after)
+			if err != nil {
+				return nil, false
+			}
+			value, _ = strconv.Unquote(quoted)
+			after = after[len(quoted):]
+		} else {
+			end := strings.IndexAny(after, " \t")
+			if end < 0 {
+				end = len(after)
+			}
+			value, after = after[:end], after[end:]
+		}
+		if after != "" && after[0] != ' ' && after[0] != '\t' {
+			return nil, false
+		}
+
+		values[key] = value
+		rest = strings.TrimSpace(after)
+	}
+
+	for _, keys := range [][]string{levelKeys, messageKeys} {
+		for _, k := range keys {
+			if _, ok := values[k]; ok {
+				return values, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// takeField removes and returns the first of keys present in values
+func takeField(values map[string]string, keys []string) string {
+	for _, k := range keys {
+		if v, ok := values[k]; ok {
+			delete(values, k)
+			return v
+		}
+	}
+	return ""
+}
+
+// formatLogTime shortens RFC 3339 and Unix epoch timestamps to the time of
+// day; anything else is shown as it is
+func formatLogTime(value string) string {
+	if value == "" {
+		return ""
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.Local().Format("15:04:05.000")
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+		if secs > 1e12 { // milliseconds
+			secs /= 1000
+		}
+		return time.UnixMilli(int64(math.Round(secs * 1000))).Local().Format("15:04:05.000")
+	}
+	return value
+}
+
+// plain renders the line as unstyled text, which search and filters match
+func (s *structuredLog) plain() string {
+	parts := make([]string, 0, 3+len(s.fields))
+	if s.time != "" {
+		parts = append(parts, s.time)
+	}
+	if s.level != "" {
+		parts = append(parts, fmt.Sprintf("%-5s", strings.ToUpper(s.level)))
+	}
+	if s.message != "" {
+		parts = append(parts, s.message)
+	}
+	parts = append(parts, s.fields...)
+	return strings.Join(parts, " ")
+}
+
+// render renders the line with a muted time and fields and a colored level
+func (s *structuredLog) render(theme Theme) string {
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	parts := make([]string, 0, 4)
+	if s.time != "" {
+		parts = append(parts, muted.Render(s.time))
+	}
+	if s.level != "" {
+		level := fmt.Sprintf("%-5s", strings.ToUpper(s.level))
+		parts = append(parts, lipgloss.NewStyle().Bold(true).Foreground(levelColor(s.level, theme)).Render(level))
+	}
+	if s.message != "" {
+		parts = append(parts, s.message)
+	}
+	if len(s.fields) > 0 {
+		parts = append(parts, muted.Render(strings.Join(s.fields, " ")))
+	}
+	return strings.Join(parts, " ")
+}
+
+// levelColor picks the color for a log level
+func levelColor(level string, theme Theme) lipgloss.Color {
+	switch strings.ToLower(level) {
+	case "error", "err", "fatal", "panic", "critical", "crit", "alert", "emerg", "emergency":
+		return theme.Danger
+	case "warn", "warning":
+		return theme.Warning
+	case "debug", "trace":
+		return theme.Muted
+	default:
+		return theme.Accent
+	}
+}
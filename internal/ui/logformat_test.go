@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseStructured(t *testing.T) {
+	clock := func(value string) string {
+		ts, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts.Local().Format("15:04:05.000")
+	}
+
+	tests := []struct {
+		name   string
+		line   string
+		fields []string
+		want   *structuredLog
+	}{
+		{
+			name: "json",
+			line: `{"level":"info","msg":"listening","port":8080,"ts":"2025-01-01T12:00:00.25Z"}`,
+			want: &structuredLog{time: clock("2025-01-01T12:00:00.25Z"), level: "info", message: "listening", fields: []string{"port=8080"}},
+		},
+		{
+			name: "json with timestamp prefix",
+			line: `2025-01-01T12:00:01.5Z {"level":"warn","msg":"slow query"}`,
+			want: &structuredLog{time: clock("2025-01-01T12:00:01.5Z"), level: "warn", message: "slow query"},
+		},
+		{
+			name: "own time wins over the prefix",
+			line: `2025-01-01T12:00:01Z {"time":"2025-01-01T11:59:59Z","msg":"x"}`,
+			want: &structuredLog{time: clock("2025-01-01T11:59:59Z"), message: "x"},
+		},
+		{
+			name: "epoch milliseconds",
+			line: `{"ts":1735732800123,"msg":"x"}`,
+			want: &structuredLog{time: time.UnixMilli(1735732800123).Local().Format("15:04:05.000"), message: "x"},
+		},
+		{
+			name: "nested values stay json",
+			line: `{"message":"request","req":{"id":1},"tags":["a","b"]}`,
+			want: &structuredLog{message: "request", fields: []string{`req={"id":1}`, `tags=["a","b"]`}},
+		},
+		{
+			name: "logfmt with quoted value",
+			line: `level=error msg="connection refused" retry=3 addr=10.0.0.1:5432`,
+			want: &structuredLog{level: "error", message: "connection refused", fields: []string{"addr=10.0.0.1:5432", "retry=3"}},
+		},
+		{
+			name: "logfmt with timestamp prefix",
+			line: `2025-01-01T12:00:02Z lvl=debug msg=tick`,
+			want: &structuredLog{time: clock("2025-01-01T12:00:02Z"), level: "debug", message: "tick"},
+		},
+		{
+			name:   "configured fields in order",
+			line:   `level=info msg=done user=bob retry=3 took=5ms`,
+			fields: []string{"took", "missing", "user"},
+			want:   &structuredLog{level: "info", message: "done", fields: []string{"took=5ms", "user=bob"}},
+		},
+		{
+			name: "plain text",
+			line: `GET /healthz 200 1.2ms`,
+		},
+		{
+			name: "plain text with an equals sign",
+			line: `setting retries=3 for upstream`,
+		},
+		{
+			name: "logfmt without level or message",
+			line: `a=1 b=2`,
+		},
+		{
+			name: "broken json",
+			line: `{"level":"info","msg":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseStructured(tt.line, tt.fields); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseStructured(%q) = %+v, want %+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLogfmt(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want map[string]string // nil when the line isn't logfmt
+	}{
+		{
+			name: "bare values",
+			line: `level=info msg=started`,
+			want: map[string]string{"level": "info", "msg": "started"},
+		},
+		{
+			name: "quoted value",
+			line: `msg="hello world" level=warn`,
+			want: map[string]string{"msg": "hello world", "level": "warn"},
+		},
+		{
+			name: "escaped quote",
+			line: `msg="say \"hi\"" level=info`,
+			want: map[string]string{"msg": `say "hi"`, "level": "info"},
+		},
+		{
+			name: "empty value",
+			line: `level= msg=x`,
+			want: map[string]string{"level": "", "msg": "x"},
+		},
+		{
+			name: "tabs and extra spaces",
+			line: "  level=info\tmsg=x  ",
+			want: map[string]string{"level": "info", "msg": "x"},
+		},
+		{
+			name: "unterminated quote",
+			line: `level=info msg="oops`,
+		},
+		{
+			name: "text after a quoted value",
+			line: `level=info msg="a"b`,
+		},
+		{
+			name: "key with a space",
+			line: `level=info some thing=x`,
+		},
+		{
+			name: "no equals sign",
+			line: `level=info trailing`,
+		},
+		{
+			name: "no level or message",
+			line: `user=bob id=7`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseLogfmt(tt.line)
+			if ok != (tt.want != nil) {
+				t.Fatalf("parseLogfmt(%q) ok = %v, want %v", tt.line, ok, tt.want != nil)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLogfmt(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
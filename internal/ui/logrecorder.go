@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// logFileName builds a timestamped file name for a resource's logs, such as
+// "pod-web-7d9f-20240405-150405.log"
+func logFileName(kind, name string, now time.Time) string {
+	safe := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ' ' || r == ':' {
+			return '_'
+		}
+		return r
+	}, name)
+	return fmt.Sprintf("%s-%s-%s.log", kind, safe, now.Format("20060102-150405"))
+}
+
+// saveLogs writes a snapshot of log content to a new file in dir and
+// returns its path
+func saveLogs(dir, kind, name, content string) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, logFileName(kind, name, time.Now()))
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// logRecorder tees a live log stream to a file, rotating it once it grows
+// past maxSize. Rotated files get a .1, .2, ... suffix, .1 being the newest,
+// and only maxFiles of them are kept.
+type logRecorder struct {
+	target   string // resource whose stream is recorded
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+// newLogRecorder starts recording to a new timestamped file in dir
+func newLogRecorder(dir, kind, name string, maxSize int64, maxFiles int) (*logRecorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	r := &logRecorder{
+		target:   name,
+		path:     filepath.Join(dir, logFileName(kind, name, time.Now())),
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the current file for appending
+func (r *logRecorder) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", r.path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat %s: %w", r.path, err)
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+// WriteLines appends lines to the file, rotating it first if they would
+// take it past maxSize
+func (r *logRecorder) WriteLines(lines []string) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	data := strings.Join(lines, "\n") + "\n"
+	if r.size > 0 && r.size+int64(len(data)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.file.WriteString(data)
+	r.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", r.path, err)
+	}
+	return nil
+}
+
+// rotate shifts the rotated files up by one, dropping the oldest, and
+// starts a fresh file
+func (r *logRecorder) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return fmt.Errorf("failed to close %s: %w", r.path, err)
+	}
+
+	os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxFiles))
+	for i := r.maxFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate %s: %w", r.path, err)
+	}
+	return r.open()
+}
+
+// Close stops recording
+func (r *logRecorder) Close() error {
+	return r.file.Close()
+}
@@ -2,30 +2,102 @@ package ui
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"unicode"
 
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// logEntry is a line in the log viewer. Search and filters only look at text,
+// so the source shown before it (e.g. the pod of an aggregated line) never
+// matches.
+type logEntry struct {
+	source     string         // shown before the text, if set
+	color      lipgloss.Color // of the source
+	text       string
+	notice     bool           // text is a message about the stream rather than output
+	structured *structuredLog // text parsed as JSON or logfmt, nil for plain text
+}
+
+// raw returns the entry as unstyled text, with its source if it has one
+func (e logEntry) raw() string {
+	if e.source != "" {
+		return e.source + " " + e.text
+	}
+	return e.text
+}
+
+// logPrompt is the pattern being typed into the log viewer, if any
+type logPrompt int
+
+const (
+	noPrompt logPrompt = iota
+	searchPrompt
+	filterPrompt
+)
+
 // LogViewer represents the log viewer component
 type LogViewer struct {
-	viewport     viewport.Model
-	title        string
-	content      string
-	width        int
-	height       int
-	autoScroll   bool
-	ready        bool
+	viewport   viewport.Model
+	theme      Theme
+	title      string
+	entries    []logEntry
+	maxLines   int // oldest lines are dropped beyond this, 0 for no limit
+	width      int
+	height     int
+	autoScroll bool
+	ready      bool
+
+	// Structured (JSON and logfmt) lines are pretty-printed unless raw
+	pretty bool
+	fields []string // shown after the message, empty for all
+
+	// Search highlights matches and moves between them with n/N
+	search     *regexp.Regexp
+	searchText string // as typed
+	matches    []int  // rendered line numbers containing a match
+	matchIdx   int    // index into matches of the current match, -1 for none
+
+	// Filter hides lines that don't match, or that do when inverted
+	filter       *regexp.Regexp
+	filterInvert bool
+	filterText   string   // as typed, "!" included
+	visible      []int    // indexes into entries that pass the filter
+	savedFilters []string // offered with ↑/↓ at the filter prompt
+	savedIdx     int
+
+	// lines holds the rendered visible entries, so appending only renders
+	// the new ones; everything is rendered again when the filter, search,
+	// format or size changes
+	lines []string
+
+	prompt    logPrompt
+	input     textinput.Model
+	promptErr string
 }
 
-// NewLogViewer creates a new log viewer
-func NewLogViewer(title string) LogViewer {
+// NewLogViewer creates a new log viewer that retains at most maxLines lines
+func NewLogViewer(title string, theme Theme, maxLines int) LogViewer {
+	// Static cursor, since blink messages aren't routed to the input
+	input := textinput.New()
+	input.Prompt = ""
+	input.Cursor.SetMode(cursor.CursorStatic)
+
 	return LogViewer{
 		title:      title,
+		theme:      theme,
+		maxLines:   maxLines,
 		autoScroll: true,
 		ready:      false,
+		pretty:     true,
+		matchIdx:   -1,
+		savedIdx:   -1,
+		input:      input,
 	}
 }
 
@@ -38,7 +110,7 @@ func (lv *LogViewer) SetSize(width, height int) {
 		lv.viewport = viewport.New(width, height-4) // Account for title and help
 		lv.viewport.Style = lipgloss.NewStyle().
 			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("6")).
+			BorderForeground(lv.theme.Border).
 			Padding(0, 1)
 		lv.ready = true
 	} else {
@@ -47,29 +119,191 @@ func (lv *LogViewer) SetSize(width, height int) {
 	}
 
 	// Re-set content to trigger recalculation
-	if lv.content != "" {
-		lv.SetContent(lv.content)
-	}
+	lv.render()
 }
 
-// SetContent sets the log content
-func (lv *LogViewer) SetContent(content string) {
-	lv.content = content
-	lv.viewport.SetContent(content)
+// SetTitle sets the title shown above the logs
+func (lv *LogViewer) SetTitle(title string) {
+	lv.title = title
+}
 
-	if lv.autoScroll {
-		lv.viewport.GotoBottom()
-	}
+// SetSavedFilters sets the filters offered at the filter prompt
+func (lv *LogViewer) SetSavedFilters(filters []string) {
+	lv.savedFilters = filters
+}
+
+// SetStructuredFields sets the fields of structured lines shown after the
+// message; empty shows them all. It applies to lines appended afterwards.
+func (lv *LogViewer) SetStructuredFields(fields []string) {
+	lv.fields = fields
+}
+
+// TogglePretty switches structured lines between pretty-printed and raw
+func (lv *LogViewer) TogglePretty() {
+	lv.pretty = !lv.pretty
+	lv.refilter()
+}
+
+// SetContent replaces the log content
+func (lv *LogViewer) SetContent(content string) {
+	lv.entries = nil
+	lv.visible = nil
+	lv.lines = nil
+	lv.matches = nil
+	lv.matchIdx = -1
+	lv.AppendContent(content)
 }
 
 // AppendContent appends new content to the logs
 func (lv *LogViewer) AppendContent(newContent string) {
-	if lv.content != "" {
-		lv.content += "\n" + newContent
-	} else {
-		lv.content = newContent
+	if newContent == "" {
+		lv.show()
+		return
+	}
+	lv.AppendLines(strings.Split(strings.TrimSuffix(newContent, "\n"), "\n")...)
+}
+
+// AppendLines appends lines to the logs, dropping the oldest ones once
+// more than maxLines are retained
+func (lv *LogViewer) AppendLines(lines ...string) {
+	entries := make([]logEntry, len(lines))
+	for i, line := range lines {
+		entries[i] = logEntry{text: line}
+	}
+	lv.appendEntries(entries)
+}
+
+// appendEntries appends entries, dropping the oldest ones beyond maxLines
+func (lv *LogViewer) appendEntries(entries []logEntry) {
+	for _, e := range entries {
+		if !e.notice {
+			e.structured = parseStructured(e.text, lv.fields)
+		}
+		if lv.keep(e) {
+			lv.visible = append(lv.visible, len(lv.entries))
+			line, matched := lv.renderEntry(e, false)
+			if matched {
+				lv.matches = append(lv.matches, len(lv.lines))
+			}
+			lv.lines = append(lv.lines, line)
+		}
+		lv.entries = append(lv.entries, e)
+	}
+
+	if dropped := len(lv.entries) - lv.maxLines; lv.maxLines > 0 && dropped > 0 {
+		// Copy so the dropped lines can be garbage collected
+		lv.entries = append([]logEntry(nil), lv.entries[dropped:]...)
+		visible := lv.visible[:0]
+		for _, idx := range lv.visible {
+			if idx >= dropped {
+				visible = append(visible, idx-dropped)
+			}
+		}
+		gone := len(lv.visible) - len(visible)
+		lv.visible = visible
+		lv.lines = append([]string(nil), lv.lines[gone:]...)
+
+		// Matches are line numbers, so they move up with the lines
+		matches, matchIdx := lv.matches[:0], -1
+		for i, line := range lv.matches {
+			if line >= gone {
+				if i == lv.matchIdx {
+					matchIdx = len(matches)
+				}
+				matches = append(matches, line-gone)
+			}
+		}
+		lv.matches, lv.matchIdx = matches, matchIdx
+	}
+	lv.show()
+}
+
+// keep reports whether an entry passes the filter
+func (lv *LogViewer) keep(e logEntry) bool {
+	if lv.filter == nil {
+		return true
+	}
+	return lv.filter.MatchString(lv.displayText(e)) != lv.filterInvert
+}
+
+// displayText is the unstyled text shown for an entry, which search and
+// filters match against
+func (lv *LogViewer) displayText(e logEntry) string {
+	if lv.pretty && e.structured != nil {
+		return e.structured.plain()
+	}
+	return e.text
+}
+
+// refilter recomputes the visible entries after the filter changes
+func (lv *LogViewer) refilter() {
+	lv.visible = lv.visible[:0]
+	for i, e := range lv.entries {
+		if lv.keep(e) {
+			lv.visible = append(lv.visible, i)
+		}
+	}
+	lv.matchIdx = -1
+	lv.render()
+}
+
+// render renders every visible line again and pushes them into the viewport
+func (lv *LogViewer) render() {
+	currentLine := -1
+	if lv.matchIdx >= 0 && lv.matchIdx < len(lv.matches) {
+		currentLine = lv.matches[lv.matchIdx]
+	}
+	lv.matches = lv.matches[:0]
+
+	lv.lines = make([]string, len(lv.visible))
+	for i, idx := range lv.visible {
+		line, matched := lv.renderEntry(lv.entries[idx], i == currentLine)
+		if matched {
+			lv.matches = append(lv.matches, i)
+		}
+		lv.lines[i] = line
+	}
+
+	// Keep pointing at the same line
+	lv.matchIdx = -1
+	for i, line := range lv.matches {
+		if line == currentLine {
+			lv.matchIdx = i
+		}
+	}
+
+	lv.show()
+}
+
+// renderEntry styles an entry, highlighting search matches; current marks
+// the line of the current match. It reports whether the search matched.
+func (lv *LogViewer) renderEntry(e logEntry, current bool) (string, bool) {
+	text := lv.displayText(e)
+	matched := lv.search != nil && lv.search.MatchString(text)
+	if matched {
+		style := lipgloss.NewStyle().Reverse(true)
+		if current {
+			style = lipgloss.NewStyle().Background(lv.theme.Warning).Foreground(lipgloss.Color("0"))
+		}
+		text = lv.search.ReplaceAllStringFunc(text, func(m string) string { return style.Render(m) })
+	} else if e.notice {
+		text = lipgloss.NewStyle().Foreground(lv.theme.Muted).Render(text)
+	} else if lv.pretty && e.structured != nil {
+		text = e.structured.render(lv.theme)
+	}
+	if e.source != "" {
+		text = lipgloss.NewStyle().Foreground(e.color).Render(e.source) + " " + text
+	}
+	return text, matched
+}
+
+// show pushes the rendered lines into the viewport
+func (lv *LogViewer) show() {
+	lv.viewport.SetContent(strings.Join(lv.lines, "\n"))
+
+	if lv.autoScroll {
+		lv.viewport.GotoBottom()
 	}
-	lv.SetContent(lv.content)
 }
 
 // ToggleAutoScroll toggles auto-scrolling
@@ -80,8 +314,182 @@ func (lv *LogViewer) ToggleAutoScroll() {
 	}
 }
 
+// HandleKey handles the search and filter keys: / searches, | filters (a
+// leading ! keeps the lines that don't match), n/N move between matches and
+// esc clears both. While a pattern is being typed every key goes to the
+// prompt. It reports whether the key was used.
+func (lv *LogViewer) HandleKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if lv.prompt != noPrompt {
+		return lv.updatePrompt(msg), true
+	}
+
+	switch msg.String() {
+	case "/":
+		lv.openPrompt(searchPrompt, lv.searchText)
+		return nil, true
+	case "|":
+		lv.openPrompt(filterPrompt, lv.filterText)
+		return nil, true
+	case "n", "N":
+		if lv.search == nil {
+			return nil, false
+		}
+		step := 1
+		if msg.String() == "N" {
+			step = -1
+		}
+		lv.nextMatch(step)
+		return nil, true
+	case "esc":
+		if lv.search == nil && lv.filter == nil {
+			return nil, false
+		}
+		lv.search, lv.searchText = nil, ""
+		lv.filter, lv.filterText, lv.filterInvert = nil, "", false
+		lv.refilter()
+		return nil, true
+	}
+	return nil, false
+}
+
+// Prompting reports whether a search or filter pattern is being typed
+func (lv *LogViewer) Prompting() bool {
+	return lv.prompt != noPrompt
+}
+
+// openPrompt starts typing a search or filter pattern
+func (lv *LogViewer) openPrompt(prompt logPrompt, value string) {
+	lv.prompt = prompt
+	lv.promptErr = ""
+	lv.savedIdx = -1
+	lv.input.SetValue(value)
+	lv.input.CursorEnd()
+	lv.input.Focus()
+}
+
+// updatePrompt edits the pattern being typed and applies it on enter
+func (lv *LogViewer) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		lv.prompt = noPrompt
+		lv.input.Blur()
+		return nil
+	case "enter":
+		if err := lv.apply(lv.input.Value()); err != nil {
+			lv.promptErr = err.Error()
+			return nil
+		}
+		lv.prompt = noPrompt
+		lv.input.Blur()
+		return nil
+	case "up", "down":
+		if lv.prompt != filterPrompt || len(lv.savedFilters) == 0 {
+			return nil
+		}
+		if msg.String() == "up" && lv.savedIdx < 0 {
+			lv.savedIdx = len(lv.savedFilters) - 1
+		} else if msg.String() == "up" {
+			lv.savedIdx = (lv.savedIdx - 1 + len(lv.savedFilters)) % len(lv.savedFilters)
+		} else {
+			lv.savedIdx = (lv.savedIdx + 1) % len(lv.savedFilters)
+		}
+		lv.input.SetValue(lv.savedFilters[lv.savedIdx])
+		lv.input.CursorEnd()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	lv.input, cmd = lv.input.Update(msg)
+	return cmd
+}
+
+// apply sets the typed pattern as the search or filter; an empty one clears it
+func (lv *LogViewer) apply(pattern string) error {
+	if lv.prompt == filterPrompt {
+		expr, invert := strings.CutPrefix(pattern, "!")
+		re, err := compilePattern(expr)
+		if err != nil {
+			return err
+		}
+		lv.filter, lv.filterText, lv.filterInvert = re, pattern, invert
+		lv.refilter()
+		return nil
+	}
+
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return err
+	}
+	lv.search, lv.searchText = re, pattern
+	lv.matchIdx = -1
+	lv.render()
+	if re != nil {
+		lv.nextMatch(1)
+	}
+	return nil
+}
+
+// nextMatch scrolls to the next (step 1) or previous (step -1) match,
+// wrapping around, and stops auto-scroll so the match stays in view
+func (lv *LogViewer) nextMatch(step int) {
+	if len(lv.matches) == 0 {
+		return
+	}
+
+	if lv.matchIdx < 0 {
+		// Start from the top of the screen rather than the oldest line
+		top := lv.viewport.YOffset
+		lv.matchIdx = len(lv.matches) - 1
+		if step < 0 {
+			lv.matchIdx = 0
+		}
+		for i, line := range lv.matches {
+			if step > 0 && line >= top {
+				lv.matchIdx = i
+				break
+			}
+			if step < 0 && line < top {
+				lv.matchIdx = i
+			}
+		}
+	} else {
+		lv.matchIdx = (lv.matchIdx + step + len(lv.matches)) % len(lv.matches)
+	}
+
+	lv.autoScroll = false
+	lv.render()
+	lv.viewport.SetYOffset(lv.matches[lv.matchIdx] - lv.viewport.Height/2)
+}
+
+// compilePattern compiles a search or filter regex, ignoring case unless
+// the pattern has an upper-case letter. An empty pattern gives nil.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if !strings.ContainsFunc(pattern, unicode.IsUpper) {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return re, nil
+}
+
 // Update handles messages
 func (lv *LogViewer) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "home":
+			lv.viewport.GotoTop()
+			return nil
+		case "end":
+			lv.viewport.GotoBottom()
+			return nil
+		}
+	}
+
 	var cmd tea.Cmd
 	lv.viewport, cmd = lv.viewport.Update(msg)
 	return cmd
@@ -95,23 +503,37 @@ func (lv *LogViewer) View() string {
 
 	titleStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("6")).
+		Foreground(lv.theme.Accent).
 		Padding(0, 1)
 
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(lv.theme.Muted).
 		Padding(0, 1)
 
 	statusStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("6")).
+		Foreground(lv.theme.Accent).
 		Padding(0, 1)
 
 	// Title
 	title := titleStyle.Render(fmt.Sprintf("📜 %s", lv.title))
 
-	// Help text
-	helpText := "↑/↓: scroll • PgUp/PgDn: page • Home/End: top/bottom • a: toggle auto-scroll • esc: close"
-	help := helpStyle.Render(helpText)
+	// Help text, or the pattern being typed
+	var help string
+	switch lv.prompt {
+	case searchPrompt, filterPrompt:
+		prefix := "/"
+		if lv.prompt == filterPrompt {
+			prefix = "|"
+		}
+		line := prefix + lv.input.View()
+		if lv.promptErr != "" {
+			line += "  " + lipgloss.NewStyle().Foreground(lv.theme.Danger).Render(lv.promptErr)
+		}
+		help = lipgloss.NewStyle().Padding(0, 1).Render(line)
+	default:
+		helpText := "↑/↓: scroll • Home/End: top/bottom • a: auto-scroll • /: search • n/N: next/prev • |: filter (!re inverts) • esc: clear • J: raw/pretty • W: save • L: record"
+		help = helpStyle.Render(helpText)
+	}
 
 	// Status line
 	scrollPercent := int(lv.viewport.ScrollPercent() * 100)
@@ -119,32 +541,53 @@ func (lv *LogViewer) View() string {
 	if lv.autoScroll {
 		autoScrollStatus = "[AUTO] "
 	}
-	status := statusStyle.Render(fmt.Sprintf("%s%d%% (%d lines)", autoScrollStatus, scrollPercent, lv.viewport.TotalLineCount()))
+	status := fmt.Sprintf("%s%d%% (%d lines)", autoScrollStatus, scrollPercent, len(lv.entries))
+	if !lv.pretty {
+		status += " • raw"
+	}
+	if lv.filter != nil {
+		status += fmt.Sprintf(" • filter %s: %d shown", lv.filterText, len(lv.visible))
+	}
+	if lv.search != nil {
+		current := 0
+		if lv.matchIdx >= 0 {
+			current = lv.matchIdx + 1
+		}
+		status += fmt.Sprintf(" • /%s: %d/%d", lv.searchText, current, len(lv.matches))
+	}
 
 	// Combine all parts
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
 		title,
 		lv.viewport.View(),
-		status,
+		statusStyle.Render(status),
 		help,
 	)
 }
 
-// GetContent returns the current content
+// GetContent returns the lines passing the filter as raw, unstyled text
 func (lv *LogViewer) GetContent() string {
-	return lv.content
+	var b strings.Builder
+	for _, idx := range lv.visible {
+		b.WriteString(lv.entries[idx].raw() + "\n")
+	}
+	return b.String()
 }
 
 // Clear clears the log content
 func (lv *LogViewer) Clear() {
-	lv.content = ""
+	lv.entries = nil
+	lv.visible = nil
+	lv.lines = nil
+	lv.matches = nil
+	lv.matchIdx = -1
 	lv.viewport.SetContent("")
 }
 
 // LineCount returns the number of lines in the logs
 func (lv *LogViewer) LineCount() int {
-	return len(strings.Split(lv.content, "\n"))
+	return len(lv.entries)
 }
 
 // IsAtBottom returns whether the viewport is scrolled to bottom
@@ -1,15 +1,24 @@
 package ui
 
 import (
+	"context"
 	"fmt"
+	"hash/fnv"
 	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/cursor"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/craigderington/lazystack/internal/config"
 	"github.com/craigderington/lazystack/internal/k8s"
 	"github.com/craigderington/lazystack/internal/systemd"
 )
@@ -22,8 +31,12 @@ const (
 	DeploymentsCategory
 	PodsCategory
 	ServicesCategory
+	UnitsCategory
 )
 
+// categoryCount is the number of left pane sections
+const categoryCount = 5
+
 // RightPaneTab represents tabs in the right pane
 type RightPaneTab int
 
@@ -57,11 +70,81 @@ type servicesLoadedMsg struct {
 	err      error
 }
 
-type podLogsLoadedMsg struct {
-	logs string
+type unitsLoadedMsg struct {
+	units []systemd.UnitInfo
+	err   error
+}
+
+type unitStatusLoadedMsg struct {
+	unit   string
+	status *systemd.UnitStatus
+	err    error
+}
+
+type unitActionDoneMsg struct {
+	action string
+	unit   string
+	err    error
+}
+
+type unitWatchStartedMsg struct {
+	events <-chan systemd.UnitEvent
+	cancel context.CancelFunc
+	err    error
+}
+
+type unitEventMsg struct {
+	event  systemd.UnitEvent
+	events <-chan systemd.UnitEvent
+	closed bool
+}
+
+type unitJournalLoadedMsg struct {
+	unit    string
+	entries []systemd.JournalEntry
+	err     error
+}
+
+type journalFollowMsg struct {
+	unit    string
+	gen     int // matches Model.journalGen while the stream is current
+	entries []systemd.JournalEntry
+	stream  <-chan systemd.JournalEntry
+	cancel  context.CancelFunc
+	closed  bool
+	err     error
+}
+
+type podLogsMsg struct {
+	target   string // pod or deployment name
+	gen      int    // matches Model.podLogsGen while the stream is current
+	prefixed bool   // lines come from several pods and show which
+	lines    []k8s.LogLine
+	stream   <-chan k8s.LogLine
+	cancel   context.CancelFunc
+	closed   bool
+	err      error
+}
+
+type logsSavedMsg struct {
+	path string
 	err  error
 }
 
+type topLoadedMsg struct {
+	namespace string
+	pods      []k8s.PodUsage
+	nodes     []k8s.NodeUsage
+	podsErr   error
+	nodesErr  error
+	withNodes bool // nodes are only loaded while the Top tab is shown
+}
+
+type unitUsageLoadedMsg struct {
+	at    time.Time
+	usage map[string]*systemd.UnitUsage
+}
+
 type podMetricsLoadedMsg struct {
 	metrics *k8s.PodMetrics
 	err     error
@@ -77,7 +160,118 @@ type resourceYAMLLoadedMsg struct {
 	err  error
 }
 
-type tickMsg time.Time
+type editableYAMLLoadedMsg struct {
+	resourceType string
+	name         string
+	yaml         string
+	err          error
+}
+
+type editorFinishedMsg struct {
+	edit *yamlEdit
+	err  error
+}
+
+type yamlDryRunMsg struct {
+	edit      *yamlEdit
+	unchanged bool
+}
+
+type yamlAppliedMsg struct {
+	edit *yamlEdit
+	err  error
+}
+
+type podContainersLoadedMsg struct {
+	pod        string
+	containers []k8s.PodContainer
+	err        error
+}
+
+type execFinishedMsg struct {
+	pod string
+	err error
+}
+
+type podPortsLoadedMsg struct {
+	pod   string
+	ports []k8s.ContainerPort
+	err   error
+}
+
+type servicePortsLoadedMsg struct {
+	service string
+	ports   []k8s.ServicePort
+	err     error
+}
+
+type portForwardStartedMsg struct {
+	pf  *k8s.PortForward
+	err error
+}
+
+// portForwardEventMsg is sent when a port forward loses or regains its connection
+type portForwardEventMsg struct {
+	key   string
+	pf    *k8s.PortForward
+	event k8s.PortForwardEvent
+}
+
+// portForwardEndedMsg is sent when a port forward stops or gives up reconnecting
+type portForwardEndedMsg struct {
+	key string
+	pf  *k8s.PortForward
+}
+
+type contextsLoadedMsg struct {
+	contexts []k8s.ContextInfo
+	err      error
+}
+
+type resourceEventsMsg struct {
+	events []k8s.ResourceEvent
+}
+
+// systemdTickMsg polls units when D-Bus signals are unavailable
+type systemdTickMsg time.Time
+
+// k8sTickMsg refreshes the data informers don't cover: namespaces and metrics
+type k8sTickMsg time.Time
+
+// journalTailLines is how many journal entries are loaded for a unit
+const journalTailLines = 100
+
+// podLogTailLines is how many existing log lines are shown before following
+const podLogTailLines = 100
+
+// maxLogLines caps how many lines the log viewer keeps in memory
+const maxLogLines = 5000
+
+// logWindow is a time range selectable for pod logs
+type logWindow struct {
+	label string
+	since time.Duration
+	today bool // since local midnight
+}
+
+// logWindows are cycled with w; the first shows the last podLogTailLines lines
+var logWindows = []logWindow{
+	{label: fmt.Sprintf("last %d lines", podLogTailLines)},
+	{label: "last 5m", since: 5 * time.Minute},
+	{label: "last 15m", since: 15 * time.Minute},
+	{label: "last 1h", since: time.Hour},
+	{label: "last 6h", since: 6 * time.Hour},
+	{label: "last 24h", since: 24 * time.Hour},
+	{label: "today", today: true},
+}
+
+// Journal filters cycled with b (boot) and v (priority); labels are shown in the Logs tab
+var (
+	journalBoots          = []string{"", "0", "-1"}
+	journalBootLabels     = []string{"all", "current", "previous"}
+	journalPriorities     = []string{"", "err", "warning", "info"}
+	journalPriorityLabels = []string{"all", "err", "warning", "info"}
+)
 
 // Model represents the application
 type Model struct {
@@ -85,38 +279,81 @@ type Model struct {
 	height int
 	ready  bool
 
+	cfg   *config.Config
+	theme Theme
+
 	// Left pane - multiple lists stacked vertically
 	activeCategory   CategoryType
 	namespacesList   list.Model
 	deploymentsList  list.Model
 	podsList         list.Model
 	servicesList     list.Model
+	unitsList        list.Model
 	selectedResource string
 
 	// Right pane
 	activeTab      RightPaneTab
-	logsViewport   viewport.Model
+	logViewer      LogViewer
 	statsViewport  viewport.Model
 	envViewport    viewport.Model
 	configViewport viewport.Model
+	topViewport    viewport.Model
+
+	// Top tab
+	topPods     []k8s.PodUsage
+	topNodes    []k8s.NodeUsage
+	topPodsErr  error
+	topNodesErr error
+	topSort     topSortKey
+	topSortAsc  bool
+	topLoaded   bool
+
+	// CPU and memory samples per pod and unit, keyed by historyKey
+	history map[string]*usageHistory
 
 	// K8s data
-	k8sManager       *k8s.Manager
-	k8sInitError     error
-	currentNamespace string
-	namespaces       []string
-	deployments      []k8s.DeploymentInfo
-	pods             []k8s.PodInfo
-	services         []k8s.ServiceInfo
-	currentMetrics   *k8s.PodMetrics
-	currentEnvVars   *k8s.PodEnvVars
+	k8sManager           *k8s.Manager
+	k8sInitError         error
+	currentNamespace     string
+	resourceGen          uint64 // informer generation whose events are applied
+	namespaces           []string
+	deployments          []k8s.DeploymentInfo
+	pods                 []k8s.PodInfo
+	services             []k8s.ServiceInfo
+	currentMetrics       *k8s.PodMetrics
+	currentEnvVars       *k8s.PodEnvVars
+	envCursor            int             // selected variable in the Env tab
+	envRevealed          map[string]bool // secret variables shown in the clear, by envKey
 	currentYAML          string
-	selectedResourceType string // "pod", "deployment", "service"
+	configYAML           yamlView                    // the resource YAML shown in the Config tab
+	yamlEdit             *yamlEdit                   // edit of a resource's YAML under review
+	selectedResourceType string                      // "pod", "deployment", "service", "unit"
 	activePortForwards   map[string]*k8s.PortForward // key: "podname:localport"
+	execContainers       []string                    // containers of the selected pod
+	execContainerIdx     int
+
+	// Pod log options for the Logs tab
+	logContainers []k8s.PodContainer // containers and init containers of the selected pod
+	logContainer  string             // empty for the pod's default container
+	logPrevious   bool
+	logTimestamps bool
+	logWindowIdx  int
 
 	// Systemd
 	systemdManager     *systemd.Manager
 	systemdInitError   error
+	units              []systemd.UnitInfo
+	unitsToWatch       []string // empty means all loaded service units
+	currentUnitStatus  *systemd.UnitStatus
+	journalBootIdx     int
+	journalPriorityIdx int
+	journalFollow      bool
+	journalCancel      context.CancelFunc
+	journalGen         int                // bumped per stream so stale batches are dropped
+	logRecorder        *logRecorder       // tees the displayed log stream to disk while set
+	podLogsCancel      context.CancelFunc // stops the followed pod log stream
+	podLogsGen         int                // bumped per stream so stale batches are dropped
+	unitWatchCancel    context.CancelFunc // set while unit changes arrive via D-Bus signals
 
 	// State
 	statusMessage string
@@ -124,16 +361,35 @@ type Model struct {
 
 	// Confirmation dialog
 	showConfirmDialog bool
-	confirmAction     string // "delete-pod", "delete-deployment", "scale-up", "scale-down"
+	confirmAction     string // "delete-pod", "delete-deployment", "scale-up", "scale-down", "start-unit", "stop-unit", "restart-unit"
 	confirmResource   string // Resource name to confirm action on
 	confirmReplicas   int32  // Target replica count for scale actions
 
 	// Help screen
 	showHelp bool
+
+	// Kubeconfig context picker
+	showContextPicker bool
+	contextsList      list.Model
+
+	// Port-forward manager
+	showPortForwards bool
+	portForwardsList list.Model
+
+	// Port picker for new pod and service forwards
+	showPortPicker    bool
+	portPickerTarget  string // pod or service name
+	portPickerService bool
+	portsList         list.Model
+	localPortInput    textinput.Model
+	remotePortInput   textinput.Model // used instead of portsList when the pod declares no ports
+	editingLocalPort  bool
 }
 
 // Custom compact delegate without pipe bars
-type compactDelegate struct{}
+type compactDelegate struct {
+	accent lipgloss.Color
+}
 
 func (d compactDelegate) Height() int                             { return 1 }
 func (d compactDelegate) Spacing() int                            { return 0 }
@@ -162,7 +418,7 @@ func (d compactDelegate) Render(w io.Writer, m list.Model, index int, item list.
 
 	// Simple highlighting for selected item
 	if index == m.Index() {
-		fmt.Fprint(w, lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Bold(true).Render("> "+str))
+		fmt.Fprint(w, lipgloss.NewStyle().Foreground(d.accent).Bold(true).Render("> "+str))
 	} else {
 		fmt.Fprint(w, "  "+str)
 	}
@@ -213,10 +469,124 @@ func (i serviceItem) Description() string {
 	return fmt.Sprintf("%s | %s | %s", i.service.Type, i.service.ClusterIP, i.service.Ports)
 }
 
-// NewModel creates the model
-func NewModel() Model {
+type contextItem struct{ context k8s.ContextInfo }
+
+func (i contextItem) FilterValue() string { return i.context.Name }
+func (i contextItem) Title() string       { return i.context.Name }
+func (i contextItem) Description() string {
+	desc := fmt.Sprintf("(%s)", i.context.Cluster)
+	if i.context.Current {
+		desc = "● " + desc
+	}
+	return desc
+}
+
+// portForwardItem is a preset, an active forward, or a preset with its active forward
+type portForwardItem struct {
+	preset *config.PortForwardPreset
+	key    string // activePortForwards key, empty when not running
+	pf     *k8s.PortForward
+}
+
+func (i portForwardItem) FilterValue() string { return i.Title() }
+func (i portForwardItem) Title() string {
+	if i.preset != nil {
+		return i.preset.Name
+	}
+	return i.pf.Target()
+}
+func (i portForwardItem) Description() string {
+	if i.pf == nil {
+		return fmt.Sprintf("○ localhost:%d -> %s:%d (not running)", i.preset.LocalPort, i.preset.PodSelector, i.preset.RemotePort)
+	}
+
+	status := i.pf.Status()
+	icon := "●"
+	switch status.State {
+	case k8s.PortForwardStarting, k8s.PortForwardReconnecting:
+		icon = "○"
+	case k8s.PortForwardFailed:
+		icon = "✗"
+	}
+
+	desc := fmt.Sprintf("%s localhost:%s -> %s/%s:%s (%s", icon, i.pf.LocalPort, i.pf.Namespace, i.pf.Target(), i.pf.RemotePort, status.State)
+	if i.pf.Service != "" || i.pf.Selector != "" {
+		desc += " via " + status.PodName
+	}
+	desc += fmt.Sprintf(", %d conns, %d reconnects)", status.Connections, status.Reconnects)
+	if status.LastError != nil {
+		desc += " last error: " + status.LastError.Error()
+	}
+	return desc
+}
+
+// pickerPort is implemented by the port picker's items
+type pickerPort interface {
+	portNumber() int32
+	protocol() string
+}
+
+type containerPortItem struct{ port k8s.ContainerPort }
+
+func (i containerPortItem) portNumber() int32 { return i.port.Port }
+func (i containerPortItem) protocol() string  { return i.port.Protocol }
+
+func (i containerPortItem) FilterValue() string { return i.port.Name }
+func (i containerPortItem) Title() string {
+	return fmt.Sprintf("%d/%s", i.port.Port, i.port.Protocol)
+}
+func (i containerPortItem) Description() string {
+	if i.port.Name != "" {
+		return fmt.Sprintf("%s (container %s)", i.port.Name, i.port.Container)
+	}
+	return fmt.Sprintf("(container %s)", i.port.Container)
+}
+
+type servicePortItem struct{ port k8s.ServicePort }
+
+func (i servicePortItem) FilterValue() string { return i.port.Name }
+func (i servicePortItem) Title() string {
+	return fmt.Sprintf("%d/%s", i.port.Port, i.port.Protocol)
+}
+func (i servicePortItem) Description() string {
+	desc := "-> " + i.port.TargetPort
+	if i.port.Name != "" {
+		desc = i.port.Name + " " + desc
+	}
+	return desc
+}
+func (i servicePortItem) portNumber() int32 { return i.port.Port }
+func (i servicePortItem) protocol() string  { return i.port.Protocol }
+
+type unitItem struct{ unit systemd.UnitInfo }
+
+func (i unitItem) FilterValue() string { return i.unit.Name }
+func (i unitItem) Title() string       { return i.unit.Name }
+func (i unitItem) Description() string {
+	return fmt.Sprintf("%s %s (%s)", unitStateIcon(i.unit.ActiveState), i.unit.ActiveState, i.unit.SubState)
+}
+
+// unitStateIcon returns the status icon for a unit's active state
+func unitStateIcon(activeState string) string {
+	switch activeState {
+	case "active":
+		return "●"
+	case "failed":
+		return "✗"
+	default:
+		return "○"
+	}
+}
+
+// NewModel creates the model from the loaded configuration
+func NewModel(cfg *config.Config) (Model, error) {
+	theme, err := LookupTheme(cfg.UI.Theme)
+	if err != nil {
+		return Model{}, err
+	}
+
 	// Use custom compact delegate without pipe bars
-	delegate := compactDelegate{}
+	delegate := compactDelegate{accent: theme.Accent}
 
 	// Create separate lists for each section
 	namespacesList := list.New([]list.Item{}, delegate, 0, 0)
@@ -251,46 +621,115 @@ func NewModel() Model {
 	servicesList.SetShowPagination(false)
 	servicesList.SetShowTitle(false)
 
+	unitsList := list.New([]list.Item{}, delegate, 0, 0)
+	unitsList.Title = ""
+	unitsList.SetShowStatusBar(false)
+	unitsList.SetFilteringEnabled(false)
+	unitsList.SetShowHelp(false)
+	unitsList.SetShowPagination(false)
+	unitsList.SetShowTitle(false)
+
+	contextsList := list.New([]list.Item{}, delegate, 0, 0)
+	contextsList.Title = ""
+	contextsList.SetShowStatusBar(false)
+	contextsList.SetFilteringEnabled(false)
+	contextsList.SetShowHelp(false)
+	contextsList.SetShowPagination(false)
+	contextsList.SetShowTitle(false)
+
+	portForwardsList := list.New([]list.Item{}, delegate, 0, 0)
+	portForwardsList.Title = ""
+	portForwardsList.SetShowStatusBar(false)
+	portForwardsList.SetFilteringEnabled(false)
+	portForwardsList.SetShowHelp(false)
+	portForwardsList.SetShowPagination(false)
+	portForwardsList.SetShowTitle(false)
+
+	portsList := list.New([]list.Item{}, delegate, 0, 0)
+	portsList.Title = ""
+	portsList.SetShowStatusBar(false)
+	portsList.SetFilteringEnabled(false)
+	portsList.SetShowHelp(false)
+	portsList.SetShowPagination(false)
+	portsList.SetShowTitle(false)
+
+	// Static cursors, since blink messages aren't routed to the inputs
+	localPortInput := textinput.New()
+	localPortInput.Prompt = ""
+	localPortInput.CharLimit = 5
+	localPortInput.Cursor.SetMode(cursor.CursorStatic)
+	remotePortInput := textinput.New()
+	remotePortInput.Prompt = ""
+	remotePortInput.CharLimit = 5
+	remotePortInput.Cursor.SetMode(cursor.CursorStatic)
+
+	logViewer := NewLogViewer("Logs", theme, maxLogLines)
+	logViewer.SetSavedFilters(cfg.Logs.SavedFilters)
+	logViewer.SetStructuredFields(cfg.Logs.Fields)
+
 	// Initialize managers
-	k8sMgr, k8sErr := k8s.NewManager()
-	systemdMgr, _ := systemd.NewManager() // Initialized but not used in UI
+	k8sMgr, k8sErr := k8s.NewManager(cfg)
+	systemdMgr, systemdErr := systemd.NewManager()
 
-	// Build initialization status message (Kubernetes only)
+	// Build initialization status message
 	var statusMsg string
 	if k8sErr != nil {
 		statusMsg = fmt.Sprintf("⚠ K8s init failed: %v", k8sErr)
 	} else {
 		statusMsg = "✓ K8s connected"
 	}
+	if systemdErr != nil {
+		statusMsg += fmt.Sprintf(" | ⚠ systemd init failed: %v", systemdErr)
+	} else {
+		statusMsg += " | ✓ systemd connected"
+	}
+
+	// The manager falls back to "default" when no namespace is configured
+	currentNamespace := "default"
+	if k8sMgr != nil {
+		currentNamespace = k8sMgr.GetNamespace()
+	}
 
 	return Model{
+		cfg:                cfg,
+		theme:              theme,
 		activeCategory:     NamespacesCategory,
 		namespacesList:     namespacesList,
 		deploymentsList:    deploymentsList,
 		podsList:           podsList,
 		servicesList:       servicesList,
+		unitsList:          unitsList,
+		contextsList:       contextsList,
+		portForwardsList:   portForwardsList,
+		logViewer:          logViewer,
+		portsList:          portsList,
+		localPortInput:     localPortInput,
+		remotePortInput:    remotePortInput,
 		k8sManager:         k8sMgr,
 		k8sInitError:       k8sErr,
 		systemdManager:     systemdMgr,
-		systemdInitError:   nil, // Not tracking systemd errors in UI
-		currentNamespace:   "default",
+		systemdInitError:   systemdErr,
+		unitsToWatch:       cfg.Systemd.UnitsToWatch,
+		currentNamespace:   currentNamespace,
 		activeTab:          LogsTab,
 		namespaces:         []string{},
 		deployments:        []k8s.DeploymentInfo{},
 		pods:               []k8s.PodInfo{},
 		statusMessage:      statusMsg,
 		activePortForwards: make(map[string]*k8s.PortForward),
-	}
+		history:            make(map[string]*usageHistory),
+	}, nil
 }
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.loadNamespaces(),
-		m.loadDeployments(),
-		m.loadPods(),
-		m.loadServices(),
-		tick(),
+		m.startInformers(),
+		m.loadUnits(),
+		m.watchUnits(),
+		m.systemdTick(),
+		m.k8sTick(),
 	)
 }
 
@@ -304,6 +743,143 @@ func (m Model) loadNamespaces() tea.Cmd {
 	}
 }
 
+// startInformers starts the Kubernetes informers and begins reading their events.
+// Deployments, pods and services are then kept current by watch events rather
+// than by re-listing on every tick.
+func (m Model) startInformers() tea.Cmd {
+	if m.k8sManager == nil {
+		return nil
+	}
+	mgr := m.k8sManager
+	return func() tea.Msg {
+		mgr.StartInformers()
+		return waitForResourceEvents(mgr.Events())()
+	}
+}
+
+// maxEventBatch bounds how many informer events are applied per update
+const maxEventBatch = 500
+
+// waitForResourceEvents blocks for one informer event, then drains whatever
+// else is queued so an initial list of many objects renders in one update
+func waitForResourceEvents(events <-chan k8s.ResourceEvent) tea.Cmd {
+	return func() tea.Msg {
+		batch := []k8s.ResourceEvent{<-events}
+		for len(batch) < maxEventBatch {
+			select {
+			case event := <-events:
+				batch = append(batch, event)
+			default:
+				return resourceEventsMsg{events: batch}
+			}
+		}
+		return resourceEventsMsg{events: batch}
+	}
+}
+
+// applyResourceEvents folds informer events for the current namespace into
+// the deployment, pod and service lists
+func (m *Model) applyResourceEvents(events []k8s.ResourceEvent) {
+	var podsChanged, deploymentsChanged, servicesChanged bool
+
+	for _, event := range events {
+		if event.Namespace != m.currentNamespace || event.Generation < m.resourceGen {
+			continue
+		}
+		if event.Generation > m.resourceGen {
+			// Informers restarted; start over even if their reset was missed
+			m.resourceGen = event.Generation
+			m.pods, m.deployments, m.services = nil, nil, nil
+			podsChanged, deploymentsChanged, servicesChanged = true, true, true
+		}
+
+		switch {
+		case event.Type == k8s.ResourcesReset:
+			m.pods, m.deployments, m.services = nil, nil, nil
+			podsChanged, deploymentsChanged, servicesChanged = true, true, true
+		case event.Pod != nil:
+			m.pods = applyEvent(m.pods, *event.Pod, event.Type, func(p k8s.PodInfo) string { return p.Name })
+			podsChanged = true
+		case event.Deployment != nil:
+			m.deployments = applyEvent(m.deployments, *event.Deployment, event.Type, func(d k8s.DeploymentInfo) string { return d.Name })
+			deploymentsChanged = true
+		case event.Service != nil:
+			m.services = applyEvent(m.services, *event.Service, event.Type, func(s k8s.ServiceInfo) string { return s.Name })
+			servicesChanged = true
+		}
+	}
+
+	if podsChanged {
+		m.setPods(m.pods)
+	}
+	if deploymentsChanged {
+		m.setDeployments(m.deployments)
+	}
+	if servicesChanged {
+		m.setServices(m.services)
+	}
+}
+
+// applyEvent adds, replaces or removes item in a name-sorted slice
+func applyEvent[T any](items []T, item T, eventType k8s.EventType, name func(T) string) []T {
+	idx := sort.Search(len(items), func(i int) bool {
+		return name(items[i]) >= name(item)
+	})
+	found := idx < len(items) && name(items[idx]) == name(item)
+
+	switch {
+	case eventType == k8s.ResourceDeleted:
+		if found {
+			items = append(items[:idx], items[idx+1:]...)
+		}
+	case found:
+		items[idx] = item
+	default:
+		var zero T
+		items = append(items, zero)
+		copy(items[idx+1:], items[idx:])
+		items[idx] = item
+	}
+
+	return items
+}
+
+// setDeployments replaces the deployments shown in the left pane
+func (m *Model) setDeployments(deployments []k8s.DeploymentInfo) {
+	// Keep name order; informer event handling relies on it
+	sort.Slice(deployments, func(i, j int) bool { return deployments[i].Name < deployments[j].Name })
+	m.deployments = deployments
+	items := make([]list.Item, len(deployments))
+	for i, deploy := range deployments {
+		items[i] = deploymentItem{deployment: deploy}
+	}
+	m.deploymentsList.SetItems(items)
+}
+
+// setPods replaces the pods shown in the left pane
+func (m *Model) setPods(pods []k8s.PodInfo) {
+	// Keep name order; informer event handling relies on it
+	sort.Slice(pods, func(i, j int) bool { return pods[i].Name < pods[j].Name })
+	m.pods = pods
+	items := make([]list.Item, len(pods))
+	for i, pod := range pods {
+		items[i] = podItem{pod: pod}
+	}
+	m.podsList.SetItems(items)
+}
+
+// setServices replaces the services shown in the left pane
+func (m *Model) setServices(services []k8s.ServiceInfo) {
+	// Keep name order; informer event handling relies on it
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	m.services = services
+	items := make([]list.Item, len(services))
+	for i, svc := range services {
+		items[i] = serviceItem{service: svc}
+	}
+	m.servicesList.SetItems(items)
+}
+
 func (m Model) loadDeployments() tea.Cmd {
 	return func() tea.Msg {
 		if m.k8sManager == nil {
@@ -334,107 +910,1050 @@ func (m Model) loadServices() tea.Cmd {
 	}
 }
 
-func (m Model) loadPodLogs(podName string) tea.Cmd {
+func (m Model) loadContexts() tea.Cmd {
 	return func() tea.Msg {
 		if m.k8sManager == nil {
-			return podLogsLoadedMsg{err: fmt.Errorf("k8s manager not initialized")}
+			return contextsLoadedMsg{err: fmt.Errorf("k8s manager not initialized")}
 		}
-		logs, err := m.k8sManager.GetPodLogs(podName, 100)
-		return podLogsLoadedMsg{logs: logs, err: err}
+		contexts, err := m.k8sManager.ListContexts()
+		return contextsLoadedMsg{contexts: contexts, err: err}
 	}
 }
 
-func (m Model) loadPodMetrics(podName string) tea.Cmd {
+// switchContext reconnects to another kubeconfig context and resets
+// everything that belonged to the previous cluster
+func (m Model) switchContext(name string) (tea.Model, tea.Cmd) {
+	if err := m.k8sManager.SwitchContext(name); err != nil {
+		m.statusMessage = fmt.Sprintf("Error switching context: %v", err)
+		return m, nil
+	}
+
+	// Log streams, recordings, edits under review and port forwards all
+	// belong to the old cluster, so none of them may outlive the switch
+	m.stopPodLogs()
+	m.podLogsGen++
+	m.stopRecording()
+	m.discardYAMLEdit()
+	for key, pf := range m.activePortForwards {
+		m.k8sManager.StopPortForward(pf)
+		delete(m.activePortForwards, key)
+	}
+	m.refreshPortForwardsList()
+
+	m.currentNamespace = m.k8sManager.GetNamespace()
+	m.namespaces = nil
+	m.namespacesList.SetItems(nil)
+	if m.selectedResourceType != "unit" {
+		m.selectedResource = ""
+		m.selectedResourceType = ""
+		m.currentMetrics = nil
+		m.currentEnvVars = nil
+		m.currentYAML = ""
+		m.logViewer.Clear()
+	}
+	m.statusMessage = fmt.Sprintf("Switched to context: %s", name)
+	return m, m.loadNamespaces()
+}
+
+func (m Model) loadUnits() tea.Cmd {
+	filter := m.unitFilter()
 	return func() tea.Msg {
-		if m.k8sManager == nil {
-			return podMetricsLoadedMsg{err: fmt.Errorf("k8s manager not initialized")}
+		if m.systemdManager == nil {
+			return unitsLoadedMsg{err: fmt.Errorf("systemd manager not initialized")}
 		}
-		metrics, err := m.k8sManager.GetPodMetrics(podName)
-		return podMetricsLoadedMsg{metrics: metrics, err: err}
+		if len(m.unitsToWatch) > 0 {
+			units, err := m.systemdManager.ListUnitsByNames(m.unitsToWatch)
+			return unitsLoadedMsg{units: units, err: err}
+		}
+		units, err := m.systemdManager.ListUnits()
+		if err != nil {
+			return unitsLoadedMsg{err: err}
+		}
+		// Without a watch list, show only service units to keep the section readable
+		services := make([]systemd.UnitInfo, 0, len(units))
+		for _, u := range units {
+			if filter(u.Name) {
+				services = append(services, u)
+			}
+		}
+		return unitsLoadedMsg{units: services}
 	}
 }
 
-func (m Model) loadPodEnvVars(podName string) tea.Cmd {
+// unitFilter reports whether a unit belongs in the units section
+func (m Model) unitFilter() func(string) bool {
+	if len(m.unitsToWatch) == 0 {
+		return func(name string) bool {
+			return strings.HasSuffix(name, ".service")
+		}
+	}
+	watched := make(map[string]bool, len(m.unitsToWatch))
+	for _, name := range m.unitsToWatch {
+		watched[name] = true
+	}
+	return func(name string) bool {
+		return watched[name]
+	}
+}
+
+// watchUnits subscribes to unit state changes so the units section updates
+// as soon as systemd reports a change instead of on the next tick
+func (m Model) watchUnits() tea.Cmd {
+	mgr := m.systemdManager
+	filter := m.unitFilter()
 	return func() tea.Msg {
-		if m.k8sManager == nil {
-			return podEnvVarsLoadedMsg{err: fmt.Errorf("k8s manager not initialized")}
+		if mgr == nil {
+			return unitWatchStartedMsg{err: fmt.Errorf("systemd manager not initialized")}
 		}
-		envVars, err := m.k8sManager.GetPodEnvVars(podName)
-		return podEnvVarsLoadedMsg{envVars: envVars, err: err}
+		ctx, cancel := context.WithCancel(context.Background())
+		events, err := mgr.WatchUnits(ctx, filter)
+		if err != nil {
+			cancel()
+			return unitWatchStartedMsg{err: err}
+		}
+		return unitWatchStartedMsg{events: events, cancel: cancel}
 	}
 }
 
-func (m Model) loadResourceYAML() tea.Cmd {
+// waitForUnitEvent reads the next unit state change
+func waitForUnitEvent(events <-chan systemd.UnitEvent) tea.Cmd {
 	return func() tea.Msg {
-		if m.k8sManager == nil {
-			return resourceYAMLLoadedMsg{err: fmt.Errorf("k8s manager not initialized")}
+		event, ok := <-events
+		return unitEventMsg{event: event, events: events, closed: !ok}
+	}
+}
+
+// applyUnitChange updates or inserts a unit in the units section
+func (m *Model) applyUnitChange(unit systemd.UnitInfo) {
+	idx := sort.Search(len(m.units), func(i int) bool {
+		return m.units[i].Name >= unit.Name
+	})
+	if idx < len(m.units) && m.units[idx].Name == unit.Name {
+		m.units[idx] = unit
+		m.unitsList.SetItem(idx, unitItem{unit: unit})
+		return
+	}
+	m.units = append(m.units, systemd.UnitInfo{})
+	copy(m.units[idx+1:], m.units[idx:])
+	m.units[idx] = unit
+	m.unitsList.InsertItem(idx, unitItem{unit: unit})
+}
+
+func (m Model) loadUnitStatus(unitName string) tea.Cmd {
+	return func() tea.Msg {
+		if m.systemdManager == nil {
+			return unitStatusLoadedMsg{unit: unitName, err: fmt.Errorf("systemd manager not initialized")}
 		}
+		status, err := m.systemdManager.GetUnitStatus(unitName)
+		return unitStatusLoadedMsg{unit: unitName, status: status, err: err}
+	}
+}
 
-		var yaml string
+// runUnitAction runs a start/stop/restart job in the background, since
+// systemd jobs can take a while to complete
+func (m Model) runUnitAction(action, unitName string) tea.Cmd {
+	return func() tea.Msg {
+		if m.systemdManager == nil {
+			return unitActionDoneMsg{action: action, unit: unitName, err: fmt.Errorf("systemd manager not initialized")}
+		}
 		var err error
-
-		switch m.selectedResourceType {
-		case "pod":
-			yaml, err = m.k8sManager.GetPodYAML(m.selectedResource)
-		case "deployment":
-			yaml, err = m.k8sManager.GetDeploymentYAML(m.selectedResource)
-		case "service":
-			yaml, err = m.k8sManager.GetServiceYAML(m.selectedResource)
+		switch action {
+		case "start-unit":
+			err = m.systemdManager.StartUnit(unitName)
+		case "stop-unit":
+			err = m.systemdManager.StopUnit(unitName)
+		case "restart-unit":
+			err = m.systemdManager.RestartUnit(unitName)
 		default:
-			err = fmt.Errorf("unknown resource type: %s", m.selectedResourceType)
+			err = fmt.Errorf("unknown unit action: %s", action)
 		}
+		return unitActionDoneMsg{action: action, unit: unitName, err: err}
+	}
+}
 
-		return resourceYAMLLoadedMsg{yaml: yaml, err: err}
+// journalOptions returns the journal options for a unit using the active filters
+func (m Model) journalOptions(unitName string) systemd.JournalOptions {
+	return systemd.JournalOptions{
+		Unit:     unitName,
+		Lines:    journalTailLines,
+		Priority: journalPriorities[m.journalPriorityIdx],
+		Boot:     journalBoots[m.journalBootIdx],
 	}
 }
 
-func tick() tea.Cmd {
-	return tea.Tick(5*time.Second, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
+func (m Model) loadUnitJournal(unitName string) tea.Cmd {
+	opts := m.journalOptions(unitName)
+	return func() tea.Msg {
+		if m.systemdManager == nil {
+			return unitJournalLoadedMsg{unit: unitName, err: fmt.Errorf("systemd manager not initialized")}
+		}
+		entries, err := m.systemdManager.GetUnitJournal(opts)
+		return unitJournalLoadedMsg{unit: unitName, entries: entries, err: err}
+	}
 }
 
-// Update handles messages
-func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmds []tea.Cmd
+// startJournalFollow starts streaming a unit's journal. The returned command
+// delivers the first batch of entries; later batches are read by waitForJournal.
+func (m *Model) startJournalFollow(unitName string) tea.Cmd {
+	m.stopJournalFollow()
+	m.logViewer.Clear()
+	if m.systemdManager == nil {
+		return nil
+	}
 
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		// Handle confirmation dialog keys first
-		if m.showConfirmDialog {
-			switch msg.String() {
-			case "y", "Y":
-				// User confirmed - perform the action
-				m.showConfirmDialog = false
-				return m.performConfirmedAction()
-			case "n", "N", "esc", "q":
-				// User cancelled
-				m.showConfirmDialog = false
-				m.statusMessage = "Action cancelled"
-				return m, nil
-			}
-			// Ignore other keys when dialog is showing
-			return m, nil
-		}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.journalCancel = cancel
+	m.journalFollow = true
+	m.journalGen++
+	gen := m.journalGen
+	opts := m.journalOptions(unitName)
+	mgr := m.systemdManager
 
-		// Handle help screen
-		if m.showHelp {
-			switch msg.String() {
-			case "?", "q", "esc":
-				m.showHelp = false
-				return m, nil
-			}
-			// Ignore other keys when help is showing
-			return m, nil
+	return func() tea.Msg {
+		stream, err := mgr.FollowUnitJournal(ctx, opts)
+		if err != nil {
+			cancel()
+			return journalFollowMsg{unit: unitName, gen: gen, err: err, closed: true}
 		}
+		return readJournalBatch(unitName, gen, stream, cancel)
+	}
+}
 
-		switch msg.String() {
-		case "ctrl+c", "q":
+// stopJournalFollow stops any active journal stream
+func (m *Model) stopJournalFollow() {
+	if m.journalCancel != nil {
+		m.journalCancel()
+		m.journalCancel = nil
+	}
+	m.journalFollow = false
+}
+
+// waitForJournal reads the next batch of followed journal entries
+func waitForJournal(msg journalFollowMsg) tea.Cmd {
+	return func() tea.Msg {
+		return readJournalBatch(msg.unit, msg.gen, msg.stream, msg.cancel)
+	}
+}
+
+// readJournalBatch blocks for one entry, then drains whatever else is
+// already buffered so bursts of log lines render as a single update
+func readJournalBatch(unitName string, gen int, stream <-chan systemd.JournalEntry, cancel context.CancelFunc) tea.Msg {
+	msg := journalFollowMsg{unit: unitName, gen: gen, stream: stream, cancel: cancel}
+
+	entry, ok := <-stream
+	if !ok {
+		msg.closed = true
+		return msg
+	}
+	msg.entries = append(msg.entries, entry)
+
+	for {
+		select {
+		case entry, ok := <-stream:
+			if !ok {
+				msg.closed = true
+				return msg
+			}
+			msg.entries = append(msg.entries, entry)
+		default:
+			return msg
+		}
+	}
+}
+
+// selectUnit makes a unit the selected resource and loads its status and journal
+func (m *Model) selectUnit(unitName string) tea.Cmd {
+	m.stopJournalFollow()
+	m.stopPodLogs()
+	m.stopRecording()
+	m.selectedResource = unitName
+	m.selectedResourceType = "unit"
+	m.activeTab = LogsTab
+	m.currentUnitStatus = nil
+	m.statsViewport.SetContent(m.renderUnitStats())
+	return tea.Batch(m.loadUnitStatus(unitName), m.loadUnitJournal(unitName), m.loadUnitUsage([]string{unitName}))
+}
+
+// journalHeader describes the active journal filters for the Logs tab
+func (m Model) journalHeader() string {
+	follow := "off"
+	if m.journalFollow {
+		follow = "on"
+	}
+	return fmt.Sprintf("Journal: %s | boot: %s | priority: %s | follow: %s",
+		m.selectedResource,
+		journalBootLabels[m.journalBootIdx],
+		journalPriorityLabels[m.journalPriorityIdx],
+		follow)
+}
+
+// journalLines formats journal entries as log viewer lines
+func journalLines(entries []systemd.JournalEntry) []string {
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = entry.String()
+	}
+	return lines
+}
+
+// startPodLogs streams the selected pod's logs into the log viewer, replacing
+// any earlier stream. The returned command delivers the first batch of lines;
+// later batches are read by waitForPodLogs.
+func (m *Model) startPodLogs(podName string) tea.Cmd {
+	m.stopPodLogs()
+	m.logViewer.Clear()
+	if m.k8sManager == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.podLogsCancel = cancel
+	m.podLogsGen++
+	gen := m.podLogsGen
+	mgr := m.k8sManager
+	opts := m.podLogOptions()
+
+	return func() tea.Msg {
+		stream, err := mgr.FollowPodLogs(ctx, podName, opts)
+		if err != nil {
+			cancel()
+			return podLogsMsg{target: podName, gen: gen, err: err, closed: true}
+		}
+		return readPodLogBatch(podLogsMsg{target: podName, gen: gen, stream: stream, cancel: cancel})
+	}
+}
+
+// selectPodLogs resets the log container for a newly selected pod and starts
+// streaming its logs. The previous/timestamps/window options carry over.
+func (m *Model) selectPodLogs(podName string) tea.Cmd {
+	m.stopRecording()
+	m.logContainers = nil
+	m.logContainer = ""
+	return m.startPodLogs(podName)
+}
+
+// podLogOptions builds the log options chosen in the Logs tab
+func (m Model) podLogOptions() k8s.LogOptions {
+	opts := k8s.LogOptions{
+		Container:  m.logContainer,
+		Previous:   m.logPrevious,
+		Timestamps: m.logTimestamps,
+	}
+
+	window := logWindows[m.logWindowIdx]
+	switch {
+	case window.since > 0:
+		opts.Since = window.since
+	case window.today:
+		now := time.Now()
+		opts.SinceTime = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	default:
+		opts.TailLines = podLogTailLines
+	}
+	return opts
+}
+
+// podLogsTitle describes the pod, container and log options for the Logs tab
+func (m Model) podLogsTitle() string {
+	container := m.logContainer
+	if container == "" {
+		container = "default container"
+	}
+
+	var flags []string
+	if m.logPrevious {
+		flags = append(flags, "previous instance")
+	}
+	if m.logTimestamps {
+		flags = append(flags, "timestamps")
+	}
+	flags = append(flags, logWindows[m.logWindowIdx].label)
+	if m.podLogsCancel != nil {
+		flags = append(flags, "following")
+	} else {
+		flags = append(flags, "ended")
+	}
+
+	return fmt.Sprintf("Logs: %s [%s] (%s)", m.selectedResource, container, strings.Join(flags, ", "))
+}
+
+// cycleLogContainer moves the log stream to the next or previous container
+func (m *Model) cycleLogContainer(step int) tea.Cmd {
+	if len(m.logContainers) < 2 {
+		return nil
+	}
+
+	idx := 0
+	for i, c := range m.logContainers {
+		if c.Name == m.logContainer {
+			idx = i
+		}
+	}
+	idx = (idx + step + len(m.logContainers)) % len(m.logContainers)
+	m.logContainer = m.logContainers[idx].Name
+	return m.startPodLogs(m.selectedResource)
+}
+
+// startDeploymentLogs streams the logs of every pod of a deployment into the
+// log viewer, each line prefixed with its pod and container in a color picked
+// per pod. Pods joining or leaving during a rollout are picked up as they go.
+func (m *Model) startDeploymentLogs(deploymentName string) tea.Cmd {
+	m.stopPodLogs()
+	m.logViewer.Clear()
+	if m.k8sManager == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.podLogsCancel = cancel
+	m.podLogsGen++
+	gen := m.podLogsGen
+	mgr := m.k8sManager
+	opts := m.podLogOptions()
+	opts.Container, opts.Previous = "", false
+
+	return func() tea.Msg {
+		stream, err := mgr.FollowDeploymentLogs(ctx, deploymentName, opts)
+		if err != nil {
+			cancel()
+			return podLogsMsg{target: deploymentName, gen: gen, err: err, closed: true}
+		}
+		return readPodLogBatch(podLogsMsg{target: deploymentName, gen: gen, prefixed: true, stream: stream, cancel: cancel})
+	}
+}
+
+// selectDeploymentLogs starts streaming the logs of a newly selected deployment
+func (m *Model) selectDeploymentLogs(deploymentName string) tea.Cmd {
+	m.stopRecording()
+	return m.startDeploymentLogs(deploymentName)
+}
+
+// deploymentLogsTitle describes the deployment and log options for the Logs tab
+func (m Model) deploymentLogsTitle() string {
+	flags := []string{"all pods"}
+	if m.logTimestamps {
+		flags = append(flags, "timestamps")
+	}
+	flags = append(flags, logWindows[m.logWindowIdx].label)
+	if m.podLogsCancel != nil {
+		flags = append(flags, "following")
+	} else {
+		flags = append(flags, "ended")
+	}
+
+	return fmt.Sprintf("Logs: deploy/%s (%s)", m.selectedResource, strings.Join(flags, ", "))
+}
+
+// logPrefixColors are assigned to pods in aggregated logs by name
+var logPrefixColors = []lipgloss.Color{"2", "3", "4", "5", "6", "9", "10", "11", "12", "13", "14"}
+
+// logEntries converts streamed lines for the log viewer. Aggregated lines are
+// prefixed with their pod and container, each pod keeping the same color for
+// as long as it runs.
+func logEntries(lines []k8s.LogLine, prefixed bool) []logEntry {
+	entries := make([]logEntry, len(lines))
+	for i, line := range lines {
+		entries[i] = logEntry{text: line.Text, notice: line.Notice}
+		if prefixed {
+			h := fnv.New32a()
+			h.Write([]byte(line.Pod))
+			color := logPrefixColors[h.Sum32()%uint32(len(logPrefixColors))]
+			entries[i].source, entries[i].color = line.Pod+" "+line.Container, color
+		}
+	}
+	return entries
+}
+
+// logsSource names the resource whose logs are shown, for log file names
+func (m Model) logsSource() (kind, name string, ok bool) {
+	switch m.selectedResourceType {
+	case "pod":
+		return "pod", m.selectedResource, true
+	case "deployment":
+		return "deploy", m.selectedResource, true
+	case "unit":
+		return "unit", m.selectedResource, true
+	}
+	return "", "", false
+}
+
+// saveLogs writes the log lines currently shown to a timestamped file
+func (m Model) saveLogs() tea.Cmd {
+	kind, name, ok := m.logsSource()
+	if !ok {
+		return nil
+	}
+	dir := m.cfg.Logs.Dir
+	content := m.logViewer.GetContent()
+
+	return func() tea.Msg {
+		path, err := saveLogs(dir, kind, name, content)
+		return logsSavedMsg{path: path, err: err}
+	}
+}
+
+// toggleRecording starts or stops teeing the displayed log stream to disk
+func (m *Model) toggleRecording() {
+	if m.logRecorder != nil {
+		path := m.logRecorder.path
+		m.stopRecording()
+		m.statusMessage = fmt.Sprintf("Stopped recording logs to %s", path)
+		return
+	}
+
+	kind, name, ok := m.logsSource()
+	if !ok {
+		return
+	}
+	recorder, err := newLogRecorder(m.cfg.Logs.Dir, kind, name, m.cfg.Logs.RecordMaxSize(), m.cfg.Logs.RecordFiles)
+	if err != nil {
+		m.statusMessage = fmt.Sprintf("Error recording logs: %v", err)
+		return
+	}
+	m.logRecorder = recorder
+	m.statusMessage = fmt.Sprintf("Recording logs to %s", recorder.path)
+}
+
+// recordLines tees newly streamed lines of target to the recording, if any
+func (m *Model) recordLines(target string, lines []string) {
+	if m.logRecorder == nil || m.logRecorder.target != target {
+		return
+	}
+	if err := m.logRecorder.WriteLines(lines); err != nil {
+		m.stopRecording()
+		m.statusMessage = fmt.Sprintf("Stopped recording logs: %v", err)
+	}
+}
+
+// stopRecording stops teeing logs to disk
+func (m *Model) stopRecording() {
+	if m.logRecorder != nil {
+		m.logRecorder.Close()
+		m.logRecorder = nil
+	}
+}
+
+// logsTitle marks a log title while its stream is being recorded
+func (m Model) logsTitle(title string) string {
+	if m.logRecorder != nil {
+		return title + " ● REC"
+	}
+	return title
+}
+
+// stopPodLogs stops any active pod or deployment log stream
+func (m *Model) stopPodLogs() {
+	if m.podLogsCancel != nil {
+		m.podLogsCancel()
+		m.podLogsCancel = nil
+	}
+}
+
+// waitForPodLogs reads the next batch of streamed log lines
+func waitForPodLogs(msg podLogsMsg) tea.Cmd {
+	return func() tea.Msg {
+		return readPodLogBatch(podLogsMsg{target: msg.target, gen: msg.gen, prefixed: msg.prefixed, stream: msg.stream, cancel: msg.cancel})
+	}
+}
+
+// readPodLogBatch blocks for one line, then drains whatever else is already
+// buffered so bursts of output render as a single update
+func readPodLogBatch(msg podLogsMsg) tea.Msg {
+	stream := msg.stream
+
+	line, ok := <-stream
+	if !ok {
+		msg.closed = true
+		return msg
+	}
+	msg.lines = append(msg.lines, line)
+
+	for {
+		select {
+		case line, ok := <-stream:
+			if !ok {
+				msg.closed = true
+				return msg
+			}
+			msg.lines = append(msg.lines, line)
+		default:
+			return msg
+		}
+	}
+}
+
+func (m Model) loadPodMetrics(podName string) tea.Cmd {
+	return func() tea.Msg {
+		if m.k8sManager == nil {
+			return podMetricsLoadedMsg{err: fmt.Errorf("k8s manager not initialized")}
+		}
+		metrics, err := m.k8sManager.GetPodMetrics(podName)
+		return podMetricsLoadedMsg{metrics: metrics, err: err}
+	}
+}
+
+func (m Model) loadPodEnvVars(podName string) tea.Cmd {
+	return func() tea.Msg {
+		if m.k8sManager == nil {
+			return podEnvVarsLoadedMsg{err: fmt.Errorf("k8s manager not initialized")}
+		}
+		envVars, err := m.k8sManager.GetPodEnvVars(podName)
+		return podEnvVarsLoadedMsg{envVars: envVars, err: err}
+	}
+}
+
+func (m Model) loadPodContainers(podName string) tea.Cmd {
+	return func() tea.Msg {
+		if m.k8sManager == nil {
+			return podContainersLoadedMsg{pod: podName, err: fmt.Errorf("k8s manager not initialized")}
+		}
+		containers, err := m.k8sManager.GetPodContainers(podName)
+		return podContainersLoadedMsg{pod: podName, containers: containers, err: err}
+	}
+}
+
+// openShell suspends the TUI and runs an interactive shell in the selected container
+func (m Model) openShell() tea.Cmd {
+	podName := m.selectedResource
+	container := ""
+	if len(m.execContainers) > 0 {
+		container = m.execContainers[m.execContainerIdx]
+	}
+	session := m.k8sManager.NewExecSession(podName, container, m.cfg.Kubernetes.ExecShells)
+	return tea.Exec(session, func(err error) tea.Msg {
+		return execFinishedMsg{pod: podName, err: err}
+	})
+}
+
+// startPortForward starts a forward in the background, since it waits for the
+// connection to the pod to be established
+func (m Model) startPortForward(podName, localPort, remotePort string) tea.Cmd {
+	return func() tea.Msg {
+		pf, err := m.k8sManager.StartPortForward("", podName, localPort, remotePort)
+		return portForwardStartedMsg{pf: pf, err: err}
+	}
+}
+
+func (m Model) loadPodPorts(podName string) tea.Cmd {
+	return func() tea.Msg {
+		ports, err := m.k8sManager.GetPodPorts(podName)
+		return podPortsLoadedMsg{pod: podName, ports: ports, err: err}
+	}
+}
+
+func (m Model) loadServicePorts(serviceName string) tea.Cmd {
+	return func() tea.Msg {
+		ports, err := m.k8sManager.GetServicePorts(serviceName)
+		return servicePortsLoadedMsg{service: serviceName, ports: ports, err: err}
+	}
+}
+
+// openPortPicker shows the port picker for a pod or service, falling back to
+// a typed remote port when a pod declares none
+func (m *Model) openPortPicker(target string, service bool, items []list.Item) {
+	m.portsList.SetSize(46, max(min(len(items), m.height-14), 1))
+	m.portsList.SetItems(items)
+	m.portsList.Select(0)
+
+	m.showPortPicker = true
+	m.portPickerTarget = target
+	m.portPickerService = service
+	m.editingLocalPort = false
+	m.localPortInput.Blur()
+	m.remotePortInput.SetValue("")
+	m.remotePortInput.Blur()
+	if len(items) == 0 {
+		m.remotePortInput.Focus()
+	}
+	m.suggestLocalPort()
+}
+
+// pickedRemotePort returns the selected or typed remote port, 0 if there is none
+func (m Model) pickedRemotePort() int {
+	if item, ok := m.portsList.SelectedItem().(pickerPort); ok {
+		return int(item.portNumber())
+	}
+	port, _ := strconv.Atoi(m.remotePortInput.Value())
+	return port
+}
+
+// suggestLocalPort defaults the local port to the remote one, or to a free
+// port when that is taken or privileged
+func (m *Model) suggestLocalPort() {
+	remote := m.pickedRemotePort()
+	if remote <= 0 {
+		m.localPortInput.SetValue("")
+		return
+	}
+	local, err := k8s.FreeLocalPort(remote)
+	if err != nil {
+		local = remote
+	}
+	m.localPortInput.SetValue(strconv.Itoa(local))
+}
+
+// updatePortPicker handles keys while the port picker is open. tab moves
+// between the remote port and the editable local port.
+func (m Model) updatePortPicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	hasPorts := len(m.portsList.Items()) > 0
+
+	switch msg.String() {
+	case "esc":
+		m.showPortPicker = false
+		return m, nil
+	case "tab", "shift+tab":
+		m.editingLocalPort = !m.editingLocalPort
+		if m.editingLocalPort {
+			m.remotePortInput.Blur()
+			return m, m.localPortInput.Focus()
+		}
+		m.localPortInput.Blur()
+		if !hasPorts {
+			return m, m.remotePortInput.Focus()
+		}
+		return m, nil
+	case "enter":
+		if item, ok := m.portsList.SelectedItem().(pickerPort); ok && item.protocol() != "TCP" {
+			m.statusMessage = fmt.Sprintf("Cannot forward %d/%s: only TCP ports can be forwarded", item.portNumber(), item.protocol())
+			return m, nil
+		}
+		remote := m.pickedRemotePort()
+		local, err := strconv.Atoi(m.localPortInput.Value())
+		if remote <= 0 || remote > 65535 || err != nil || local <= 0 || local > 65535 {
+			m.statusMessage = "Ports must be between 1 and 65535"
+			return m, nil
+		}
+		m.showPortPicker = false
+		m.statusMessage = fmt.Sprintf("Starting port-forward to %s...", m.portPickerTarget)
+		if m.portPickerService {
+			return m, m.startServicePortForward(m.portPickerTarget, strconv.Itoa(local), strconv.Itoa(remote))
+		}
+		return m, m.startPortForward(m.portPickerTarget, strconv.Itoa(local), strconv.Itoa(remote))
+	}
+
+	var cmd tea.Cmd
+	switch {
+	case m.editingLocalPort:
+		m.localPortInput, cmd = m.localPortInput.Update(msg)
+	case !hasPorts:
+		m.remotePortInput, cmd = m.remotePortInput.Update(msg)
+		m.suggestLocalPort()
+	default:
+		prev := m.portsList.Index()
+		m.portsList, cmd = m.portsList.Update(msg)
+		if m.portsList.Index() != prev {
+			m.suggestLocalPort()
+		}
+	}
+	return m, cmd
+}
+
+// startServicePortForward starts a service forward in the background
+func (m Model) startServicePortForward(serviceName, localPort, servicePort string) tea.Cmd {
+	return func() tea.Msg {
+		pf, err := m.k8sManager.StartServicePortForward("", serviceName, localPort, servicePort)
+		return portForwardStartedMsg{pf: pf, err: err}
+	}
+}
+
+// startPresetPortForward forwards to a ready pod matching a preset's selector
+func (m Model) startPresetPortForward(preset config.PortForwardPreset) tea.Cmd {
+	return func() tea.Msg {
+		pf, err := m.k8sManager.StartSelectorPortForward(preset.Namespace, preset.PodSelector,
+			strconv.Itoa(preset.LocalPort), strconv.Itoa(preset.RemotePort))
+		if err != nil {
+			return portForwardStartedMsg{err: fmt.Errorf("%s: %w", preset.Name, err)}
+		}
+		return portForwardStartedMsg{pf: pf}
+	}
+}
+
+// waitForPortForward reports a forward's reconnects and its end, so they
+// surface in the status bar. It is re-issued after every event.
+func waitForPortForward(key string, pf *k8s.PortForward) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case event := <-pf.Events():
+			return portForwardEventMsg{key: key, pf: pf, event: event}
+		case <-pf.Done():
+			return portForwardEndedMsg{key: key, pf: pf}
+		}
+	}
+}
+
+func (m Model) loadResourceYAML() tea.Cmd {
+	return func() tea.Msg {
+		if m.k8sManager == nil {
+			return resourceYAMLLoadedMsg{err: fmt.Errorf("k8s manager not initialized")}
+		}
+
+		var yaml string
+		var err error
+
+		switch m.selectedResourceType {
+		case "pod":
+			yaml, err = m.k8sManager.GetPodYAML(m.selectedResource)
+		case "deployment":
+			yaml, err = m.k8sManager.GetDeploymentYAML(m.selectedResource)
+		case "service":
+			yaml, err = m.k8sManager.GetServiceYAML(m.selectedResource)
+		default:
+			err = fmt.Errorf("unknown resource type: %s", m.selectedResourceType)
+		}
+
+		return resourceYAMLLoadedMsg{yaml: yaml, err: err}
+	}
+}
+
+// editResourceYAML loads the selected resource's YAML for editing
+func (m Model) editResourceYAML() tea.Cmd {
+	mgr, resourceType, name := m.k8sManager, m.selectedResourceType, m.selectedResource
+	return func() tea.Msg {
+		content, err := mgr.GetEditableYAML(resourceType, name)
+		return editableYAMLLoadedMsg{resourceType: resourceType, name: name, yaml: content, err: err}
+	}
+}
+
+// openEditor suspends the TUI and opens an edit's file in the editor
+func openEditor(edit *yamlEdit) tea.Cmd {
+	return tea.ExecProcess(editorCommand(edit.path), func(err error) tea.Msg {
+		return editorFinishedMsg{edit: edit, err: err}
+	})
+}
+
+// dryRunYAMLEdit reads the edited file and dry-runs applying it, to show the
+// diff and any validation errors or conflicts before anything is changed
+func (m Model) dryRunYAMLEdit(edit *yamlEdit) tea.Cmd {
+	mgr := m.k8sManager
+	return func() tea.Msg {
+		data, err := os.ReadFile(edit.path)
+		if err != nil {
+			e := *edit
+			e.err = fmt.Errorf("failed to read %s: %w", edit.path, err)
+			return yamlDryRunMsg{edit: &e}
+		}
+		if string(data) == edit.original {
+			return yamlDryRunMsg{edit: edit, unchanged: true}
+		}
+
+		e := *edit
+		e.content, e.diff, e.err, e.conflict = string(data), "", nil, false
+		result, err := mgr.ApplyYAML(e.resourceType, e.name, e.content, true, false)
+		if k8s.IsFieldManagerConflict(err) {
+			// Dry-run a forced apply to show what forcing would change
+			e.err, e.conflict = err, true
+			if result, err = mgr.ApplyYAML(e.resourceType, e.name, e.content, true, true); err != nil {
+				e.err, e.conflict = err, false
+			}
+		} else if err != nil {
+			e.err = err
+		}
+		if result != nil {
+			e.diff = yamlDiff(e.resourceType+"/"+e.name, result.Live, result.Result)
+		}
+		return yamlDryRunMsg{edit: &e}
+	}
+}
+
+// applyYAMLEdit applies a reviewed edit, forcing it if it conflicts with
+// other field managers
+func (m Model) applyYAMLEdit(edit *yamlEdit) tea.Cmd {
+	mgr := m.k8sManager
+	return func() tea.Msg {
+		_, err := mgr.ApplyYAML(edit.resourceType, edit.name, edit.content, false, edit.conflict)
+		return yamlAppliedMsg{edit: edit, err: err}
+	}
+}
+
+// reviewingYAMLEdit reports whether an edit of the selected resource is
+// waiting to be applied
+func (m Model) reviewingYAMLEdit() bool {
+	return m.yamlEdit != nil && m.yamlEdit.resourceType == m.selectedResourceType &&
+		m.yamlEdit.name == m.selectedResource
+}
+
+// discardYAMLEdit drops the edit under review and its temporary file
+func (m *Model) discardYAMLEdit() {
+	if m.yamlEdit != nil {
+		os.Remove(m.yamlEdit.path)
+		m.yamlEdit = nil
+	}
+}
+
+func (m Model) systemdTick() tea.Cmd {
+	return tea.Tick(m.cfg.Systemd.RefreshInterval(), func(t time.Time) tea.Msg {
+		return systemdTickMsg(t)
+	})
+}
+
+func (m Model) k8sTick() tea.Cmd {
+	return tea.Tick(m.cfg.Kubernetes.RefreshInterval(), func(t time.Time) tea.Msg {
+		return k8sTickMsg(t)
+	})
+}
+
+// leftPaneWidth returns the width of the left pane from ui.split_ratio
+func (m Model) leftPaneWidth() int {
+	return int(float64(m.width) * m.cfg.UI.SplitRatio)
+}
+
+// Update handles messages
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// Handle confirmation dialog keys first
+		if m.showConfirmDialog {
+			switch msg.String() {
+			case "y", "Y":
+				// User confirmed - perform the action
+				m.showConfirmDialog = false
+				return m.performConfirmedAction()
+			case "n", "N", "esc", "q":
+				// User cancelled
+				m.showConfirmDialog = false
+				m.statusMessage = "Action cancelled"
+				return m, nil
+			}
+			// Ignore other keys when dialog is showing
+			return m, nil
+		}
+
+		// Handle context picker
+		if m.showContextPicker {
+			switch msg.String() {
+			case "K", "q", "esc":
+				m.showContextPicker = false
+				return m, nil
+			case "enter":
+				m.showContextPicker = false
+				if item, ok := m.contextsList.SelectedItem().(contextItem); ok && !item.context.Current {
+					return m.switchContext(item.context.Name)
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.contextsList, cmd = m.contextsList.Update(msg)
+			return m, cmd
+		}
+
+		// Handle port picker
+		if m.showPortPicker {
+			return m.updatePortPicker(msg)
+		}
+
+		// Handle port-forward manager
+		if m.showPortForwards {
+			switch msg.String() {
+			case "F", "q", "esc":
+				m.showPortForwards = false
+				return m, nil
+			case "enter":
+				item, ok := m.portForwardsList.SelectedItem().(portForwardItem)
+				if !ok || item.preset == nil || (item.pf != nil && item.pf.State() != k8s.PortForwardFailed) {
+					return m, nil
+				}
+				m.statusMessage = fmt.Sprintf("Starting port-forward %s...", item.preset.Name)
+				return m, m.startPresetPortForward(*item.preset)
+			case "x", "d":
+				if item, ok := m.portForwardsList.SelectedItem().(portForwardItem); ok && item.pf != nil {
+					m.k8sManager.StopPortForward(item.pf)
+					delete(m.activePortForwards, item.key)
+					m.statusMessage = fmt.Sprintf("Stopped port-forward localhost:%s -> %s:%s", item.pf.LocalPort, item.pf.Target(), item.pf.RemotePort)
+					m.refreshPortForwardsList()
+				}
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.portForwardsList, cmd = m.portForwardsList.Update(msg)
+			return m, cmd
+		}
+
+		// Handle help screen
+		if m.showHelp {
+			switch msg.String() {
+			case "?", "q", "esc":
+				m.showHelp = false
+				return m, nil
+			}
+			// Ignore other keys when help is showing
+			return m, nil
+		}
+
+		// Apply or discard an edit of the YAML under review
+		if m.activeTab == ConfigTab && m.reviewingYAMLEdit() {
+			switch msg.String() {
+			case "y":
+				if m.yamlEdit.err != nil && !m.yamlEdit.conflict {
+					m.statusMessage = "Fix the errors (E) or discard the changes (n) first"
+					return m, nil
+				}
+				m.statusMessage = fmt.Sprintf("Applying changes to %s %s...", m.yamlEdit.resourceType, m.yamlEdit.name)
+				return m, m.applyYAMLEdit(m.yamlEdit)
+			case "E":
+				edit := *m.yamlEdit
+				edit.original = edit.content
+				return m, openEditor(&edit)
+			case "n", "esc":
+				m.statusMessage = fmt.Sprintf("Discarded changes to %s %s", m.yamlEdit.resourceType, m.yamlEdit.name)
+				m.discardYAMLEdit()
+				m.refreshConfig()
+				return m, nil
+			}
+		}
+
+		// Fold sections of the YAML shown and show or hide its status
+		if m.activeTab == ConfigTab && m.showingResourceYAML() {
+			switch msg.String() {
+			case "[", "]":
+				step := 1
+				if msg.String() == "[" {
+					step = -1
+				}
+				m.configYAML.Move(step)
+				m.refreshConfig()
+				return m, nil
+			case "z":
+				m.configYAML.Toggle()
+				m.refreshConfig()
+				return m, nil
+			case "Z":
+				m.configYAML.ToggleAll()
+				m.refreshConfig()
+				return m, nil
+			case "h":
+				m.configYAML.ToggleStatus()
+				m.refreshConfig()
+				return m, nil
+			}
+		}
+
+		// Search and filter the logs while they're shown. An open prompt gets
+		// every key, even if the selection changed under it, so global
+		// bindings like q, s and r can't fire while a pattern is typed.
+		if m.logViewer.Prompting() ||
+			(m.activeTab == LogsTab && m.selectedResourceType != "service" && m.selectedResource != "") {
+			if cmd, ok := m.logViewer.HandleKey(msg); ok {
+				return m, cmd
+			}
+		}
+
+		switch msg.String() {
+		case "ctrl+c", "q":
 			// Cleanup port forwards
 			if m.k8sManager != nil {
 				for _, pf := range m.activePortForwards {
 					m.k8sManager.StopPortForward(pf)
 				}
+				m.k8sManager.StopInformers()
+			}
+			m.stopJournalFollow()
+			m.stopPodLogs()
+			m.stopRecording()
+			m.discardYAMLEdit()
+			if m.unitWatchCancel != nil {
+				m.unitWatchCancel()
 			}
 			if m.systemdManager != nil {
 				m.systemdManager.Close()
@@ -446,14 +1965,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.showHelp = !m.showHelp
 			return m, nil
 
+		// Open kubeconfig context picker
+		case "K":
+			if m.k8sManager == nil {
+				return m, nil
+			}
+			m.showContextPicker = true
+			return m, m.loadContexts()
+
+		// Open port-forward manager
+		case "F":
+			if m.k8sManager == nil {
+				return m, nil
+			}
+			m.showPortForwards = true
+			m.refreshPortForwardsList()
+			return m, nil
+
 		// Switch active category with tab
 		case "tab":
 			// Cycle forward through categories
-			m.activeCategory = (m.activeCategory + 1) % 4
+			m.activeCategory = (m.activeCategory + 1) % categoryCount
 			return m, nil
 		case "shift+tab":
 			// Cycle backward through categories
-			m.activeCategory = (m.activeCategory - 1 + 4) % 4
+			m.activeCategory = (m.activeCategory - 1 + categoryCount) % categoryCount
 			return m, nil
 		case "1":
 			m.activeCategory = NamespacesCategory
@@ -466,7 +2002,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedResource = item.deployment.Name
 					m.selectedResourceType = "deployment"
 					m.activeTab = ConfigTab
-					return m, m.loadResourceYAML()
+					logsCmd := m.selectDeploymentLogs(item.deployment.Name)
+					return m, tea.Batch(logsCmd, m.loadResourceYAML())
 				}
 			}
 			return m, nil
@@ -478,10 +2015,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedResource = item.pod.Name
 					m.selectedResourceType = "pod"
 					m.activeTab = LogsTab
+					logsCmd := m.selectPodLogs(item.pod.Name)
 					return m, tea.Batch(
-						m.loadPodLogs(item.pod.Name),
+						logsCmd,
 						m.loadPodMetrics(item.pod.Name),
 						m.loadPodEnvVars(item.pod.Name),
+						m.loadPodContainers(item.pod.Name),
 						m.loadResourceYAML(),
 					)
 				}
@@ -494,19 +2033,69 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if item, ok := selected.(serviceItem); ok {
 					m.selectedResource = item.service.Name
 					m.selectedResourceType = "service"
+					m.stopPodLogs()
+					m.stopRecording()
 					m.activeTab = ConfigTab
 					return m, m.loadResourceYAML()
 				}
 			}
 			return m, nil
+		case "5":
+			m.activeCategory = UnitsCategory
+			// Auto-select first unit when switching to this category
+			if selected := m.unitsList.SelectedItem(); selected != nil {
+				if item, ok := selected.(unitItem); ok {
+					cmd := m.selectUnit(item.unit.Name)
+					return m, cmd
+				}
+			}
+			return m, nil
 
 		// Switch tabs
 		case "l":
 			m.activeTab = LogsTab
 			return m, nil
 		case "s":
+			// Start unit when the units section is focused
+			if m.activeCategory == UnitsCategory {
+				return m.confirmUnitAction("start-unit")
+			}
 			m.activeTab = StatsTab
 			return m, nil
+		case "S":
+			if m.activeCategory == UnitsCategory {
+				return m.confirmUnitAction("stop-unit")
+			}
+			return m, nil
+		case "R":
+			if m.activeCategory == UnitsCategory {
+				return m.confirmUnitAction("restart-unit")
+			}
+			return m, nil
+		case "a":
+			// Toggle log auto-scroll
+			if m.activeTab == LogsTab {
+				m.logViewer.ToggleAutoScroll()
+			}
+			return m, nil
+		case "J":
+			// Toggle pretty-printing of JSON and logfmt log lines
+			if m.activeTab == LogsTab {
+				m.logViewer.TogglePretty()
+			}
+			return m, nil
+		case "W":
+			// Save the shown log lines to a file
+			if m.activeTab == LogsTab {
+				return m, m.saveLogs()
+			}
+			return m, nil
+		case "L":
+			// Toggle recording the live log stream to disk
+			if m.activeTab == LogsTab {
+				m.toggleRecording()
+			}
+			return m, nil
 		case "e":
 			m.activeTab = EnvTab
 			return m, nil
@@ -515,14 +2104,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		case "t":
 			m.activeTab = TopTab
+			return m, m.loadTop()
+		case "C", "M", "N":
+			// Sort the Top tab by CPU, memory or name; again to reverse
+			if m.activeTab == TopTab {
+				key := map[string]topSortKey{"C": topSortCPU, "M": topSortMemory, "N": topSortName}[msg.String()]
+				if key == m.topSort {
+					m.topSortAsc = !m.topSortAsc
+				} else {
+					m.topSort, m.topSortAsc = key, key == topSortName
+				}
+				m.topViewport.SetContent(m.renderTop())
+			}
 			return m, nil
 		case "x":
 			m.activeTab = ExecTab
 			return m, nil
+		case "E":
+			// Edit the selected resource's YAML in $EDITOR
+			if m.activeTab == ConfigTab && m.k8sManager != nil && m.selectedResource != "" &&
+				m.selectedResourceType != "unit" {
+				m.statusMessage = fmt.Sprintf("Opening %s in the editor...", m.selectedResource)
+				return m, m.editResourceYAML()
+			}
+			return m, nil
+		case "V":
+			// Reveal or mask the selected secret variable
+			if m.activeTab == EnvTab {
+				vars := m.envVarList()
+				if m.envCursor < len(vars) && vars[m.envCursor].env.Secret {
+					key := vars[m.envCursor].key()
+					m.envRevealed[key] = !m.envRevealed[key]
+					m.refreshEnv()
+				}
+			}
+			return m, nil
 
 		case "r":
 			m.statusMessage = "Refreshing..."
-			return m, tea.Batch(m.loadNamespaces(), m.loadDeployments(), m.loadPods(), m.loadServices())
+			cmds := []tea.Cmd{m.loadNamespaces(), m.loadUnits()}
+			// Running informers re-list themselves; a List next to them could
+			// land after newer events and roll the lists back
+			if m.k8sManager == nil || !m.k8sManager.RestartInformers() {
+				cmds = append(cmds, m.loadDeployments(), m.loadPods(), m.loadServices())
+			}
+			return m, tea.Batch(cmds...)
 
 		case "enter":
 			// Handle selection based on active category
@@ -534,7 +2160,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.k8sManager.SetNamespace(item.name)
 					m.statusMessage = fmt.Sprintf("Switched to namespace: %s", item.name)
 					m.activeCategory = PodsCategory
-					return m, tea.Batch(m.loadDeployments(), m.loadPods(), m.loadServices())
+					return m, nil
 				}
 			case DeploymentsCategory:
 				selected := m.deploymentsList.SelectedItem()
@@ -543,7 +2169,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedResourceType = "deployment"
 					m.statusMessage = fmt.Sprintf("Selected deployment: %s", item.deployment.Name)
 					m.activeTab = ConfigTab
-					return m, m.loadResourceYAML()
+					logsCmd := m.selectDeploymentLogs(item.deployment.Name)
+					return m, tea.Batch(logsCmd, m.loadResourceYAML())
 				}
 			case PodsCategory:
 				selected := m.podsList.SelectedItem()
@@ -552,13 +2179,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedResourceType = "pod"
 					m.statusMessage = fmt.Sprintf("Selected: %s", item.pod.Name)
 					m.activeTab = LogsTab
+					logsCmd := m.selectPodLogs(item.pod.Name)
 					return m, tea.Batch(
-						m.loadPodLogs(item.pod.Name),
+						logsCmd,
 						m.loadPodMetrics(item.pod.Name),
 						m.loadPodEnvVars(item.pod.Name),
+						m.loadPodContainers(item.pod.Name),
 						m.loadResourceYAML(),
 					)
 				}
+			case UnitsCategory:
+				selected := m.unitsList.SelectedItem()
+				if item, ok := selected.(unitItem); ok {
+					m.statusMessage = fmt.Sprintf("Selected unit: %s", item.unit.Name)
+					cmd := m.selectUnit(item.unit.Name)
+					return m, cmd
+				}
 			}
 			return m, nil
 
@@ -612,25 +2248,119 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "p":
-			// Start port forward for selected pod
-			if m.activeCategory == PodsCategory {
-				selected := m.podsList.SelectedItem()
-				if item, ok := selected.(podItem); ok {
-					if m.k8sManager != nil {
-						// Use a default port mapping (8080:80 for web apps, or 8080:8080)
-						localPort := "8080"
-						remotePort := "80" // Common default, could make this configurable
-
-						pf, err := m.k8sManager.StartPortForward(item.pod.Name, localPort, remotePort)
-						if err != nil {
-							m.statusMessage = fmt.Sprintf("Error starting port-forward: %v", err)
-						} else {
-							key := fmt.Sprintf("%s:%s", item.pod.Name, localPort)
-							m.activePortForwards[key] = pf
-							m.statusMessage = fmt.Sprintf("Port-forward started: localhost:%s -> %s:%s", localPort, item.pod.Name, remotePort)
-						}
+			// Start port forward for selected pod or service
+			if m.k8sManager == nil {
+				return m, nil
+			}
+			switch m.activeCategory {
+			case PodsCategory:
+				if item, ok := m.podsList.SelectedItem().(podItem); ok {
+					m.statusMessage = fmt.Sprintf("Loading ports for %s...", item.pod.Name)
+					return m, m.loadPodPorts(item.pod.Name)
+				}
+			case ServicesCategory:
+				if item, ok := m.servicesList.SelectedItem().(serviceItem); ok {
+					m.statusMessage = fmt.Sprintf("Loading ports for %s...", item.service.Name)
+					return m, m.loadServicePorts(item.service.Name)
+				}
+			}
+			return m, nil
+
+		case "i":
+			// Open an interactive shell in the selected pod
+			if m.activeTab == ExecTab && m.selectedResourceType == "pod" && m.k8sManager != nil {
+				m.statusMessage = fmt.Sprintf("Opening shell in %s...", m.selectedResource)
+				return m, m.openShell()
+			}
+			return m, nil
+
+		case "[", "]":
+			// Move between variables in the Env tab
+			if m.activeTab == EnvTab {
+				if n := len(m.envVarList()); n > 0 {
+					step := 1
+					if msg.String() == "[" {
+						step = n - 1
 					}
+					m.envCursor = (m.envCursor + step) % n
+					m.refreshEnv()
+				}
+				return m, nil
+			}
+			// Cycle the exec or log container for multi-container pods
+			if m.activeTab == ExecTab && len(m.execContainers) > 1 {
+				step := 1
+				if msg.String() == "[" {
+					step = len(m.execContainers) - 1
+				}
+				m.execContainerIdx = (m.execContainerIdx + step) % len(m.execContainers)
+			}
+			if m.activeTab == LogsTab && m.selectedResourceType == "pod" {
+				step := 1
+				if msg.String() == "[" {
+					step = -1
+				}
+				cmd := m.cycleLogContainer(step)
+				return m, cmd
+			}
+			return m, nil
+
+		case "o", "T", "w":
+			// Toggle previous-instance logs (o) or timestamps (T), or cycle
+			// the time window (w) for the selected pod's logs. Deployment
+			// logs follow every pod, so only T and w apply to them
+			if m.activeTab == LogsTab && m.selectedResourceType == "pod" {
+				switch msg.String() {
+				case "o":
+					m.logPrevious = !m.logPrevious
+				case "T":
+					m.logTimestamps = !m.logTimestamps
+				case "w":
+					m.logWindowIdx = (m.logWindowIdx + 1) % len(logWindows)
+				}
+				cmd := m.startPodLogs(m.selectedResource)
+				return m, cmd
+			}
+			if m.activeTab == LogsTab && m.selectedResourceType == "deployment" && msg.String() != "o" {
+				if msg.String() == "T" {
+					m.logTimestamps = !m.logTimestamps
+				} else {
+					m.logWindowIdx = (m.logWindowIdx + 1) % len(logWindows)
+				}
+				cmd := m.startDeploymentLogs(m.selectedResource)
+				return m, cmd
+			}
+			return m, nil
+
+		case "f":
+			// Toggle following the selected unit's journal
+			if m.selectedResourceType == "unit" {
+				if m.journalFollow {
+					m.stopJournalFollow()
+					m.statusMessage = "Stopped following journal"
+					return m, nil
+				}
+				m.activeTab = LogsTab
+				m.statusMessage = fmt.Sprintf("Following journal for %s", m.selectedResource)
+				cmd := m.startJournalFollow(m.selectedResource)
+				return m, cmd
+			}
+			return m, nil
+
+		case "b", "v":
+			// Cycle journal boot (b) or priority (v) filter for the selected unit
+			if m.selectedResourceType == "unit" {
+				if msg.String() == "b" {
+					m.journalBootIdx = (m.journalBootIdx + 1) % len(journalBoots)
+				} else {
+					m.journalPriorityIdx = (m.journalPriorityIdx + 1) % len(journalPriorities)
 				}
+				m.activeTab = LogsTab
+				if m.journalFollow {
+					cmd := m.startJournalFollow(m.selectedResource)
+					return m, cmd
+				}
+				return m, m.loadUnitJournal(m.selectedResource)
 			}
 			return m, nil
 
@@ -663,12 +2393,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			m.statusMessage = fmt.Sprintf("Error loading deployments: %v", msg.err)
 		} else {
-			m.deployments = msg.deployments
-			items := make([]list.Item, len(msg.deployments))
-			for i, deploy := range msg.deployments {
-				items[i] = deploymentItem{deployment: deploy}
-			}
-			m.deploymentsList.SetItems(items)
+			m.setDeployments(msg.deployments)
 		}
 		return m, nil
 
@@ -676,12 +2401,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			m.statusMessage = fmt.Sprintf("Error loading pods: %v", msg.err)
 		} else {
-			m.pods = msg.pods
-			items := make([]list.Item, len(msg.pods))
-			for i, pod := range msg.pods {
-				items[i] = podItem{pod: pod}
-			}
-			m.podsList.SetItems(items)
+			m.setPods(msg.pods)
 			if m.statusMessage == "Refreshing..." {
 				m.statusMessage = fmt.Sprintf("Loaded %d pods from %s", len(msg.pods), m.currentNamespace)
 			}
@@ -692,21 +2412,292 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if msg.err != nil {
 			m.statusMessage = fmt.Sprintf("Error loading services: %v", msg.err)
 		} else {
-			m.services = msg.services
-			items := make([]list.Item, len(msg.services))
-			for i, svc := range msg.services {
-				items[i] = serviceItem{service: svc}
+			m.setServices(msg.services)
+		}
+		return m, nil
+
+	case podContainersLoadedMsg:
+		if msg.pod != m.selectedResource {
+			return m, nil
+		}
+		m.execContainers = nil
+		m.execContainerIdx = 0
+		m.logContainers = nil
+		if msg.err != nil {
+			return m, nil
+		}
+		for _, c := range msg.containers {
+			if !c.Init {
+				m.execContainers = append(m.execContainers, c.Name)
+			}
+			// The log stream started on the default container; name it
+			if c.Default && m.logContainer == "" {
+				m.logContainer = c.Name
+			}
+		}
+		m.logContainers = msg.containers
+		return m, nil
+
+	case execFinishedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Exec error: %v", msg.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Shell session in %s ended", msg.pod)
+		}
+		return m, nil
+
+	case podPortsLoadedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error loading ports: %v", msg.err)
+			return m, nil
+		}
+		items := make([]list.Item, len(msg.ports))
+		for i, p := range msg.ports {
+			items[i] = containerPortItem{port: p}
+		}
+		m.openPortPicker(msg.pod, false, items)
+		return m, nil
+
+	case servicePortsLoadedMsg:
+		if msg.err == nil && len(msg.ports) == 0 {
+			msg.err = fmt.Errorf("service %s exposes no ports", msg.service)
+		}
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error loading ports: %v", msg.err)
+			return m, nil
+		}
+		items := make([]list.Item, len(msg.ports))
+		for i, p := range msg.ports {
+			items[i] = servicePortItem{port: p}
+		}
+		m.openPortPicker(msg.service, true, items)
+		return m, nil
+
+	case portForwardStartedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error starting port-forward: %v", msg.err)
+			return m, nil
+		}
+		// A forward that held this local port before must have died
+		for key, pf := range m.activePortForwards {
+			if pf.LocalPort == msg.pf.LocalPort {
+				delete(m.activePortForwards, key)
+			}
+		}
+		key := fmt.Sprintf("%s:%s", msg.pf.Target(), msg.pf.LocalPort)
+		m.activePortForwards[key] = msg.pf
+		m.statusMessage = fmt.Sprintf("Port-forward started: localhost:%s -> %s:%s", msg.pf.LocalPort, msg.pf.Target(), msg.pf.RemotePort)
+		m.refreshPortForwardsList()
+		return m, waitForPortForward(key, msg.pf)
+
+	case portForwardEventMsg:
+		switch msg.event.Type {
+		case k8s.PortForwardDisconnected:
+			m.statusMessage = fmt.Sprintf("Port-forward localhost:%s lost %s: %v, reconnecting...",
+				msg.pf.LocalPort, msg.event.PodName, msg.event.Err)
+		case k8s.PortForwardReconnected:
+			m.statusMessage = fmt.Sprintf("Port-forward localhost:%s reconnected to %s:%s",
+				msg.pf.LocalPort, msg.event.PodName, msg.pf.RemotePort)
+		}
+		m.refreshPortForwardsList()
+		return m, waitForPortForward(msg.key, msg.pf)
+
+	case portForwardEndedMsg:
+		// Failed forwards stay listed with their error until stopped or restarted
+		status := msg.pf.Status()
+		if status.State == k8s.PortForwardFailed {
+			m.statusMessage = fmt.Sprintf("Port-forward localhost:%s -> %s:%s failed: %v",
+				msg.pf.LocalPort, msg.pf.Target(), msg.pf.RemotePort, status.LastError)
+		} else if m.activePortForwards[msg.key] == msg.pf {
+			delete(m.activePortForwards, msg.key)
+		}
+		m.refreshPortForwardsList()
+		return m, nil
+
+	case contextsLoadedMsg:
+		if msg.err != nil {
+			m.showContextPicker = false
+			m.statusMessage = fmt.Sprintf("Error loading contexts: %v", msg.err)
+			return m, nil
+		}
+		items := make([]list.Item, len(msg.contexts))
+		selected := 0
+		for i, ctx := range msg.contexts {
+			items[i] = contextItem{context: ctx}
+			if ctx.Current {
+				selected = i
+			}
+		}
+		m.contextsList.SetSize(56, max(min(len(items), m.height-12), 1))
+		m.contextsList.SetItems(items)
+		m.contextsList.Select(selected)
+		return m, nil
+
+	case resourceEventsMsg:
+		m.applyResourceEvents(msg.events)
+		return m, waitForResourceEvents(m.k8sManager.Events())
+
+	case unitsLoadedMsg:
+		if msg.err != nil {
+			// Systemd being unavailable is already reported at startup
+			if m.systemdManager != nil {
+				m.statusMessage = fmt.Sprintf("Error loading units: %v", msg.err)
+			}
+		} else {
+			m.units = msg.units
+			items := make([]list.Item, len(msg.units))
+			for i, u := range msg.units {
+				items[i] = unitItem{unit: u}
 			}
-			m.servicesList.SetItems(items)
+			m.unitsList.SetItems(items)
+		}
+		return m, nil
+
+	case unitWatchStartedMsg:
+		if msg.err != nil {
+			// Fall back to polling units on each tick
+			return m, nil
+		}
+		m.unitWatchCancel = msg.cancel
+		return m, waitForUnitEvent(msg.events)
+
+	case unitEventMsg:
+		if msg.closed {
+			m.unitWatchCancel = nil
+			return m, nil
+		}
+		if msg.event.Err != nil {
+			m.statusMessage = fmt.Sprintf("Unit watch error: %v", msg.event.Err)
+			return m, waitForUnitEvent(msg.events)
+		}
+		m.applyUnitChange(msg.event.Unit)
+		cmds := []tea.Cmd{waitForUnitEvent(msg.events)}
+		if m.selectedResourceType == "unit" && m.selectedResource == msg.event.Unit.Name {
+			cmds = append(cmds, m.loadUnitStatus(msg.event.Unit.Name))
+		}
+		return m, tea.Batch(cmds...)
+
+	case unitStatusLoadedMsg:
+		if m.selectedResourceType != "unit" || msg.unit != m.selectedResource {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.currentUnitStatus = nil
+			m.configViewport.SetContent(fmt.Sprintf("Error loading unit status: %v", msg.err))
+		} else {
+			m.currentUnitStatus = msg.status
+			m.configViewport.SetContent(m.renderUnitStatus())
+		}
+		return m, nil
+
+	case unitActionDoneMsg:
+		verb := strings.TrimSuffix(msg.action, "-unit")
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.statusMessage = fmt.Sprintf("Unit %s: %s succeeded", msg.unit, verb)
+		}
+		cmds := []tea.Cmd{m.loadUnits()}
+		if m.selectedResourceType == "unit" && m.selectedResource == msg.unit {
+			cmds = append(cmds, m.loadUnitStatus(msg.unit))
+		}
+		return m, tea.Batch(cmds...)
+
+	case unitJournalLoadedMsg:
+		if msg.unit != m.selectedResource || m.journalFollow {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.logViewer.SetContent(fmt.Sprintf("Error: %v", msg.err))
+			return m, nil
+		}
+		if len(msg.entries) == 0 {
+			m.logViewer.SetContent(fmt.Sprintf("No journal entries for %s", msg.unit))
+			return m, nil
 		}
+		m.logViewer.SetContent(strings.Join(journalLines(msg.entries), "\n"))
 		return m, nil
 
-	case podLogsLoadedMsg:
+	case journalFollowMsg:
+		// Drop batches from a stream that is no longer being displayed
+		if msg.gen != m.journalGen || msg.unit != m.selectedResource || !m.journalFollow {
+			if msg.cancel != nil {
+				msg.cancel()
+			}
+			return m, nil
+		}
+		if msg.err != nil {
+			m.journalFollow = false
+			m.statusMessage = fmt.Sprintf("Error following journal: %v", msg.err)
+			return m, nil
+		}
+		lines := journalLines(msg.entries)
+		m.logViewer.AppendLines(lines...)
+		m.recordLines(msg.unit, lines)
+		if msg.closed {
+			m.journalFollow = false
+			m.statusMessage = fmt.Sprintf("Journal stream for %s ended", msg.unit)
+			return m, nil
+		}
+		return m, waitForJournal(msg)
+
+	case podLogsMsg:
+		// Drop batches from a stream that is no longer being displayed
+		if msg.gen != m.podLogsGen || msg.target != m.selectedResource ||
+			(m.selectedResourceType != "pod" && m.selectedResourceType != "deployment") {
+			if msg.cancel != nil {
+				msg.cancel()
+			}
+			return m, nil
+		}
+		if msg.err != nil {
+			m.podLogsCancel = nil
+			m.logViewer.SetContent(fmt.Sprintf("Error: %v", msg.err))
+			return m, nil
+		}
+		entries := logEntries(msg.lines, msg.prefixed)
+		m.logViewer.appendEntries(entries)
+		if m.logRecorder != nil {
+			lines := make([]string, len(entries))
+			for i, e := range entries {
+				lines[i] = e.raw()
+			}
+			m.recordLines(msg.target, lines)
+		}
+		if msg.closed {
+			m.podLogsCancel = nil
+			m.statusMessage = fmt.Sprintf("Log stream for %s ended", msg.target)
+			return m, nil
+		}
+		return m, waitForPodLogs(msg)
+
+	case logsSavedMsg:
 		if msg.err != nil {
-			m.logsViewport.SetContent(fmt.Sprintf("Error: %v", msg.err))
+			m.statusMessage = fmt.Sprintf("Error saving logs: %v", msg.err)
 		} else {
-			m.logsViewport.SetContent(msg.logs)
-			m.logsViewport.GotoBottom()
+			m.statusMessage = fmt.Sprintf("Saved logs to %s", msg.path)
+		}
+		return m, nil
+
+	case topLoadedMsg:
+		m.topPods, m.topPodsErr = msg.pods, msg.podsErr
+		if msg.withNodes {
+			m.topNodes, m.topNodesErr = msg.nodes, msg.nodesErr
+			m.topLoaded = true
+		}
+		if msg.podsErr == nil {
+			m.recordPodUsage(msg.namespace, msg.pods)
+		}
+		m.topViewport.SetContent(m.renderTop())
+		if m.selectedResourceType == "pod" && m.currentMetrics != nil {
+			m.statsViewport.SetContent(m.renderStats())
+		}
+		return m, nil
+
+	case unitUsageLoadedMsg:
+		m.recordUnitUsage(msg.at, msg.usage)
+		if m.selectedResourceType == "unit" {
+			m.statsViewport.SetContent(m.renderUnitStats())
 		}
 		return m, nil
 
@@ -728,38 +2719,111 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.envViewport.SetContent(fmt.Sprintf("Error loading environment variables: %v", msg.err))
 		} else {
 			m.currentEnvVars = msg.envVars
-			m.envViewport.SetContent(m.renderEnv())
+			m.envCursor = 0
+			m.envRevealed = make(map[string]bool)
+			m.envViewport.GotoTop()
+			m.refreshEnv()
+		}
+		return m, nil
+
+	case editableYAMLLoadedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Error loading YAML: %v", msg.err)
+			return m, nil
+		}
+		edit, err := newYAMLEdit(msg.resourceType, msg.name, msg.yaml)
+		if err != nil {
+			m.statusMessage = fmt.Sprintf("Error: %v", err)
+			return m, nil
+		}
+		m.discardYAMLEdit()
+		return m, openEditor(edit)
+
+	case editorFinishedMsg:
+		if msg.err != nil {
+			m.statusMessage = fmt.Sprintf("Editor error: %v", msg.err)
+			if m.yamlEdit == nil || m.yamlEdit.path != msg.edit.path {
+				os.Remove(msg.edit.path)
+			}
+			return m, nil
 		}
+		m.statusMessage = fmt.Sprintf("Checking changes to %s %s...", msg.edit.resourceType, msg.edit.name)
+		return m, m.dryRunYAMLEdit(msg.edit)
+
+	case yamlDryRunMsg:
+		if msg.unchanged {
+			m.statusMessage = fmt.Sprintf("No changes to %s %s", msg.edit.resourceType, msg.edit.name)
+			if m.yamlEdit == nil || m.yamlEdit.path != msg.edit.path {
+				os.Remove(msg.edit.path)
+			}
+			return m, nil
+		}
+		m.yamlEdit = msg.edit
+		m.activeTab = ConfigTab
+		m.statusMessage = fmt.Sprintf("Review changes to %s %s", msg.edit.resourceType, msg.edit.name)
+		m.configViewport.GotoTop()
+		m.refreshConfig()
 		return m, nil
 
+	case yamlAppliedMsg:
+		if m.yamlEdit == nil || m.yamlEdit.path != msg.edit.path {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.yamlEdit.err, m.yamlEdit.conflict = msg.err, k8s.IsFieldManagerConflict(msg.err)
+			m.statusMessage = "Apply failed"
+			m.refreshConfig()
+			return m, nil
+		}
+		m.statusMessage = fmt.Sprintf("Applied changes to %s %s", msg.edit.resourceType, msg.edit.name)
+		m.discardYAMLEdit()
+		return m, m.loadResourceYAML()
+
 	case resourceYAMLLoadedMsg:
 		if msg.err != nil {
 			m.currentYAML = fmt.Sprintf("Error loading YAML: %v", msg.err)
+			m.configYAML.SetContent("")
 			m.configViewport.SetContent(m.currentYAML)
 		} else {
 			m.currentYAML = msg.yaml
-			m.configViewport.SetContent(m.renderConfig())
+			m.configYAML.SetContent(msg.yaml)
+			m.configViewport.GotoTop()
+			m.refreshConfig()
 		}
 		return m, nil
 
-	case tickMsg:
-		return m, tea.Batch(m.loadDeployments(), m.loadPods(), m.loadServices(), tick())
+	case systemdTickMsg:
+		cmds := []tea.Cmd{m.systemdTick(), m.loadUnitUsage(m.sampledUnits())}
+		// Units only need polling when the D-Bus subscription is unavailable
+		if m.unitWatchCancel == nil {
+			cmds = append(cmds, m.loadUnits())
+		}
+		return m, tea.Batch(cmds...)
+
+	case k8sTickMsg:
+		cmds := []tea.Cmd{m.k8sTick(), m.loadNamespaces()}
+		if m.selectedResourceType == "pod" {
+			cmds = append(cmds, m.loadPodMetrics(m.selectedResource))
+		}
+		// Pod usage is loaded on every tick to keep the history going
+		cmds = append(cmds, m.loadTop())
+		return m, tea.Batch(cmds...)
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.ready = true
 
-		leftPaneWidth := m.width / 3
+		leftPaneWidth := m.leftPaneWidth()
 		rightPaneWidth := m.width - leftPaneWidth - 4
 
-		// Calculate section height to fit all 4 sections in terminal
+		// Calculate section height to fit all sections in terminal
 		// Total available height for left pane
 		totalAvailable := m.height - 6 // minus title, status, help
 		// Each section has: top border (1) + list items (N) + bottom border (1)
-		// So: 4 * (N + 2) = totalAvailable
-		// Therefore: N = (totalAvailable / 4) - 2
-		sectionHeight := (totalAvailable / 4) - 2
+		// So: categoryCount * (N + 2) = totalAvailable
+		// Therefore: N = (totalAvailable / categoryCount) - 2
+		sectionHeight := (totalAvailable / categoryCount) - 2
 		// Minimum 3 items per section
 		if sectionHeight < 3 {
 			sectionHeight = 3
@@ -769,21 +2833,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.deploymentsList.SetSize(leftPaneWidth-4, sectionHeight)
 		m.podsList.SetSize(leftPaneWidth-4, sectionHeight)
 		m.servicesList.SetSize(leftPaneWidth-4, sectionHeight)
+		m.unitsList.SetSize(leftPaneWidth-4, sectionHeight)
 
-		if !m.logsViewport.HighPerformanceRendering {
-			m.logsViewport = viewport.New(rightPaneWidth-4, m.height-12)
+		m.logViewer.SetSize(rightPaneWidth-4, m.height-12)
+		if !m.statsViewport.HighPerformanceRendering {
 			m.statsViewport = viewport.New(rightPaneWidth-4, m.height-12)
 			m.envViewport = viewport.New(rightPaneWidth-4, m.height-12)
 			m.configViewport = viewport.New(rightPaneWidth-4, m.height-12)
+			m.topViewport = viewport.New(rightPaneWidth-4, m.height-12)
+			m.topViewport.SetContent(m.renderTop())
 		} else {
-			m.logsViewport.Width = rightPaneWidth - 4
-			m.logsViewport.Height = m.height - 12
 			m.statsViewport.Width = rightPaneWidth - 4
 			m.statsViewport.Height = m.height - 12
 			m.envViewport.Width = rightPaneWidth - 4
 			m.envViewport.Height = m.height - 12
 			m.configViewport.Width = rightPaneWidth - 4
 			m.configViewport.Height = m.height - 12
+			m.topViewport.Width = rightPaneWidth - 4
+			m.topViewport.Height = m.height - 12
 		}
 
 		return m, nil
@@ -798,10 +2865,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if selected := m.namespacesList.SelectedItem(); selected != nil {
 			if item, ok := selected.(namespaceItem); ok {
 				if m.currentNamespace != item.name {
+					// Informers restart for the new namespace and repopulate the lists
 					m.currentNamespace = item.name
 					m.k8sManager.SetNamespace(item.name)
 					m.statusMessage = fmt.Sprintf("Switched to namespace: %s", item.name)
-					cmds = append(cmds, tea.Batch(m.loadDeployments(), m.loadPods(), m.loadServices()))
 				}
 			}
 		}
@@ -815,6 +2882,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedResourceType = "deployment"
 					m.statusMessage = fmt.Sprintf("Selected: %s", item.deployment.Name)
 					m.activeTab = ConfigTab
+					cmds = append(cmds, m.selectDeploymentLogs(item.deployment.Name))
 					cmds = append(cmds, m.loadResourceYAML())
 				}
 			}
@@ -829,9 +2897,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.selectedResourceType = "pod"
 					m.statusMessage = fmt.Sprintf("Selected: %s", item.pod.Name)
 					m.activeTab = LogsTab
-					cmds = append(cmds, m.loadPodLogs(item.pod.Name))
+					cmds = append(cmds, m.selectPodLogs(item.pod.Name))
 					cmds = append(cmds, m.loadPodMetrics(item.pod.Name))
 					cmds = append(cmds, m.loadPodEnvVars(item.pod.Name))
+					cmds = append(cmds, m.loadPodContainers(item.pod.Name))
 					cmds = append(cmds, m.loadResourceYAML())
 				}
 			}
@@ -844,20 +2913,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.selectedResource != item.service.Name {
 					m.selectedResource = item.service.Name
 					m.selectedResourceType = "service"
+					m.stopPodLogs()
+					m.stopRecording()
 					m.statusMessage = fmt.Sprintf("Selected: %s", item.service.Name)
 					m.activeTab = ConfigTab
 					cmds = append(cmds, m.loadResourceYAML())
 				}
 			}
 		}
+	case UnitsCategory:
+		m.unitsList, cmd = m.unitsList.Update(msg)
+		// Auto-select unit as user navigates
+		if selected := m.unitsList.SelectedItem(); selected != nil {
+			if item, ok := selected.(unitItem); ok {
+				if m.selectedResource != item.unit.Name {
+					m.statusMessage = fmt.Sprintf("Selected: %s", item.unit.Name)
+					cmds = append(cmds, m.selectUnit(item.unit.Name))
+				}
+			}
+		}
 	}
 	cmds = append(cmds, cmd)
 
 	// Update active viewport
 	switch m.activeTab {
 	case LogsTab:
-		m.logsViewport, cmd = m.logsViewport.Update(msg)
-		cmds = append(cmds, cmd)
+		cmds = append(cmds, m.logViewer.Update(msg))
 	case StatsTab:
 		m.statsViewport, cmd = m.statsViewport.Update(msg)
 		cmds = append(cmds, cmd)
@@ -867,6 +2948,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ConfigTab:
 		m.configViewport, cmd = m.configViewport.Update(msg)
 		cmds = append(cmds, cmd)
+	case TopTab:
+		m.topViewport, cmd = m.topViewport.Update(msg)
+		cmds = append(cmds, cmd)
 	}
 
 	return m, tea.Batch(cmds...)
@@ -886,7 +2970,7 @@ func (m Model) performConfirmedAction() (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.statusMessage = fmt.Sprintf("Deleted pod: %s", m.confirmResource)
-		return m, m.loadPods()
+		return m, nil
 
 	case "delete-deployment":
 		if m.k8sManager == nil {
@@ -899,7 +2983,7 @@ func (m Model) performConfirmedAction() (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.statusMessage = fmt.Sprintf("Deleted deployment: %s", m.confirmResource)
-		return m, m.loadDeployments()
+		return m, nil
 
 	case "scale-up", "scale-down":
 		if m.k8sManager == nil {
@@ -912,7 +2996,16 @@ func (m Model) performConfirmedAction() (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.statusMessage = fmt.Sprintf("Scaled %s to %d replicas", m.confirmResource, m.confirmReplicas)
-		return m, m.loadDeployments()
+		return m, nil
+
+	case "start-unit", "stop-unit", "restart-unit":
+		if m.systemdManager == nil {
+			m.statusMessage = "Error: systemd manager not initialized"
+			return m, nil
+		}
+		verb := strings.TrimSuffix(m.confirmAction, "-unit")
+		m.statusMessage = fmt.Sprintf("Running %s on %s...", verb, m.confirmResource)
+		return m, m.runUnitAction(m.confirmAction, m.confirmResource)
 
 	default:
 		m.statusMessage = "Unknown action"
@@ -920,13 +3013,24 @@ func (m Model) performConfirmedAction() (tea.Model, tea.Cmd) {
 	}
 }
 
+// confirmUnitAction shows the confirmation dialog for a unit action on the selected unit
+func (m Model) confirmUnitAction(action string) (tea.Model, tea.Cmd) {
+	selected := m.unitsList.SelectedItem()
+	if item, ok := selected.(unitItem); ok {
+		m.showConfirmDialog = true
+		m.confirmAction = action
+		m.confirmResource = item.unit.Name
+	}
+	return m, nil
+}
+
 // View renders the UI
 func (m Model) View() string {
 	if !m.ready {
 		return "Initializing lazystack..."
 	}
 
-	leftPaneWidth := m.width / 3
+	leftPaneWidth := m.leftPaneWidth()
 	rightPaneWidth := m.width - leftPaneWidth - 4
 
 	// Build left pane - stack all sections vertically
@@ -935,6 +3039,7 @@ func (m Model) View() string {
 		m.renderSection(m.deploymentsList, "[2] Deployments", m.activeCategory == DeploymentsCategory),
 		m.renderSection(m.podsList, "[3] Pods", m.activeCategory == PodsCategory),
 		m.renderSection(m.servicesList, "[4] Services", m.activeCategory == ServicesCategory),
+		m.renderSection(m.unitsList, "[5] Units", m.activeCategory == UnitsCategory),
 	}
 
 	leftPaneContent := lipgloss.JoinVertical(lipgloss.Left, leftPaneSections...)
@@ -949,7 +3054,7 @@ func (m Model) View() string {
 	// Right pane
 	rightPaneStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(m.theme.Border).
 		Width(rightPaneWidth).
 		Height(m.height - 6).
 		Padding(1)
@@ -962,26 +3067,30 @@ func (m Model) View() string {
 
 	// Title bar
 	titleStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("6")).
+		Foreground(m.theme.Accent).
 		Bold(true).
 		Padding(0, 1)
 
-	title := titleStyle.Render("lazystack - Kubernetes TUI")
+	title := titleStyle.Render("lazystack - systemd & Kubernetes TUI")
 
 	// Status bar
 	statusStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("6")).
+		Foreground(m.theme.Accent).
 		Bold(true).
 		Padding(0, 1)
 
-	status := statusStyle.Render(fmt.Sprintf("Namespace: %s | %s", m.currentNamespace, m.statusMessage))
+	kubeContext := "-"
+	if m.k8sManager != nil {
+		kubeContext = m.k8sManager.GetContext()
+	}
+	status := statusStyle.Render(fmt.Sprintf("Context: %s | Namespace: %s | %s", kubeContext, m.currentNamespace, m.statusMessage))
 
 	// Help
 	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(m.theme.Muted).
 		Padding(0, 1)
 
-	helpText := "?: help • tab/shift-tab: cycle • 1-4: jump • l: logs • s: stats • e: env • c: config • +/-: scale • p: port-fwd • P: stop • d: delete • r: refresh • q: quit"
+	helpText := "?: help • tab/shift-tab: cycle • 1-5: jump • l: logs • s: stats • e: env • c: config • +/-: scale • p: port-fwd • P: stop • F: forwards • d: delete • s/S/R: unit start/stop/restart • r: refresh • q: quit"
 	// Truncate help text if too wide
 	maxHelpWidth := m.width - 4 // Leave some margin
 	if len(helpText) > maxHelpWidth {
@@ -996,6 +3105,21 @@ func (m Model) View() string {
 		return m.overlayHelp(baseView)
 	}
 
+	// Overlay context picker if showing
+	if m.showContextPicker {
+		return m.overlayContextPicker(baseView)
+	}
+
+	// Overlay port picker if showing
+	if m.showPortPicker {
+		return m.overlayPortPicker(baseView)
+	}
+
+	// Overlay port-forward manager if showing
+	if m.showPortForwards {
+		return m.overlayPortForwards(baseView)
+	}
+
 	// Overlay confirmation dialog if showing
 	if m.showConfirmDialog {
 		return m.overlayConfirmDialog(baseView)
@@ -1010,25 +3134,40 @@ func (m Model) overlayHelp(baseView string) string {
 
 NAVIGATION
   tab / shift+tab    Cycle through sections
-  1-4                Jump to section (1:Namespaces 2:Deployments 3:Pods 4:Services)
+  1-5                Jump to section (1:Namespaces 2:Deployments 3:Pods 4:Services 5:Units)
   j / down           Move down in list
   k / up             Move up in list
 
 TABS (Right Panel)
-  l                  Logs tab
+  l                  Logs tab (pod and deployment logs stream live; a: toggle auto-scroll)
+                     /: search, n/N: next/prev match, |: regex filter (!re inverts), esc: clear
+                     J: show JSON/logfmt lines raw or pretty-printed
+                     W: save shown lines to a file, L: record the live stream to disk
+                     [/]: container, o: previous instance, T: timestamps, w: time window
   s                  Stats tab (resource metrics)
-  e                  Environment variables tab
-  c                  Config tab (YAML view)
-  t                  Top tab (coming soon)
-  x                  Exec tab (coming soon)
+  e                  Environment variables tab ([/]: select variable, V: reveal secret)
+  c                  Config tab (YAML view; [/]: section, z/Z: fold, h: status,
+                     E: edit in $EDITOR and apply)
+  t                  Top tab (pod and node usage; C/M/N: sort by CPU/memory/name)
+  x                  Exec tab (i: open shell, [/]: container, ctrl+]: detach)
 
 ACTIONS
   +                  Scale deployment up (increase replicas)
   -                  Scale deployment down (decrease replicas)
-  p                  Start port-forward (pod → localhost:8080)
+  p                  Port-forward the selected pod or service (pick ports)
   P                  Stop all port-forwards
+  F                  Manage port-forwards (start presets, stop forwards)
   d                  Delete selected resource (pod/deployment)
   r                  Refresh current view
+  K                  Switch kubeconfig context
+
+SYSTEMD UNITS (when Units section is focused)
+  s                  Start selected unit
+  S                  Stop selected unit
+  R                  Restart selected unit
+  f                  Follow the selected unit's journal
+  b                  Cycle journal boot filter (all/current/previous)
+  v                  Cycle journal priority filter (all/err/warning/info)
 
 GENERAL
   ?                  Toggle this help screen
@@ -1040,7 +3179,7 @@ Press ? or q to close this help screen`
 	// Help box style
 	helpBox := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("6")). // Cyan border
+		BorderForeground(m.theme.Accent).
 		Padding(1, 2).
 		Width(70).
 		Align(lipgloss.Left)
@@ -1061,6 +3200,158 @@ Press ? or q to close this help screen`
 	return helpWithPadding
 }
 
+// overlayContextPicker renders the kubeconfig context picker over the base view
+func (m Model) overlayContextPicker(baseView string) string {
+	pickerBox := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Accent).
+		Padding(1, 2).
+		Width(60)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Accent)
+
+	promptStyle := lipgloss.NewStyle().
+		Foreground(m.theme.Muted)
+
+	pickerContent := lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("Switch Kubernetes context"),
+		"",
+		m.contextsList.View(),
+		"",
+		promptStyle.Render("enter: switch • j/k: move • esc: cancel"),
+	)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		pickerBox.Render(pickerContent),
+	)
+}
+
+// refreshPortForwardsList lists the configured presets, each with its forward
+// if one is running, followed by any other forwards
+func (m *Model) refreshPortForwardsList() {
+	claimed := make(map[string]bool)
+	var items []list.Item
+
+	for i := range m.cfg.PortForwards.Presets {
+		preset := &m.cfg.PortForwards.Presets[i]
+		item := portForwardItem{preset: preset}
+		for key, pf := range m.activePortForwards {
+			if pf.LocalPort == strconv.Itoa(preset.LocalPort) {
+				item.key, item.pf = key, pf
+				claimed[key] = true
+				break
+			}
+		}
+		items = append(items, item)
+	}
+
+	keys := make([]string, 0, len(m.activePortForwards))
+	for key := range m.activePortForwards {
+		if !claimed[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		items = append(items, portForwardItem{key: key, pf: m.activePortForwards[key]})
+	}
+
+	m.portForwardsList.SetSize(66, max(min(len(items), m.height-12), 1))
+	m.portForwardsList.SetItems(items)
+}
+
+// overlayPortForwards renders the port-forward manager over the base view
+func (m Model) overlayPortForwards(baseView string) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Accent).
+		Padding(1, 2).
+		Width(70)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Accent)
+
+	promptStyle := lipgloss.NewStyle().
+		Foreground(m.theme.Muted)
+
+	listView := m.portForwardsList.View()
+	if len(m.portForwardsList.Items()) == 0 {
+		listView = promptStyle.Render("No port-forwards running and no presets configured")
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("Port-forwards"),
+		"",
+		listView,
+		"",
+		promptStyle.Render("enter: start preset • x: stop • j/k: move • esc: close"),
+	)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		box.Render(content),
+	)
+}
+
+// overlayPortPicker renders the port picker for a new forward over the base view
+func (m Model) overlayPortPicker(baseView string) string {
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.theme.Accent).
+		Padding(1, 2).
+		Width(50)
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Accent)
+
+	promptStyle := lipgloss.NewStyle().
+		Foreground(m.theme.Muted)
+
+	target := m.portPickerTarget
+	if m.portPickerService {
+		target = "svc/" + target
+	}
+
+	remote := m.portsList.View()
+	if len(m.portsList.Items()) == 0 {
+		remote = lipgloss.JoinVertical(lipgloss.Left,
+			promptStyle.Render("No container ports declared"),
+			"Remote port: "+m.remotePortInput.View())
+	}
+
+	content := lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render("Port-forward "+target),
+		"",
+		remote,
+		"",
+		"Local port:  "+m.localPortInput.View(),
+		"",
+		promptStyle.Render("enter: start • tab: edit local port • j/k: move • esc: cancel"),
+	)
+
+	return lipgloss.Place(
+		m.width,
+		m.height,
+		lipgloss.Center,
+		lipgloss.Center,
+		box.Render(content),
+	)
+}
+
 // overlayConfirmDialog renders a confirmation dialog over the base view
 func (m Model) overlayConfirmDialog(baseView string) string {
 	// Determine dialog message based on action
@@ -1074,18 +3365,25 @@ func (m Model) overlayConfirmDialog(baseView string) string {
 		message = fmt.Sprintf("Scale '%s' to %d replicas?", m.confirmResource, m.confirmReplicas)
 	case "scale-down":
 		message = fmt.Sprintf("Scale '%s' to %d replicas?", m.confirmResource, m.confirmReplicas)
+	case "start-unit":
+		message = fmt.Sprintf("Start unit '%s'?", m.confirmResource)
+	case "stop-unit":
+		message = fmt.Sprintf("Stop unit '%s'?", m.confirmResource)
+	case "restart-unit":
+		message = fmt.Sprintf("Restart unit '%s'?", m.confirmResource)
 	default:
 		message = fmt.Sprintf("Confirm action on '%s'?", m.confirmResource)
 	}
 
 	// Choose color based on action type
 	var borderColor, textColor lipgloss.Color
-	if m.confirmAction == "scale-up" || m.confirmAction == "scale-down" {
-		borderColor = lipgloss.Color("3")  // Yellow for scale actions
-		textColor = lipgloss.Color("3")    // Yellow text
+	if m.confirmAction == "scale-up" || m.confirmAction == "scale-down" ||
+		m.confirmAction == "start-unit" || m.confirmAction == "restart-unit" {
+		borderColor = m.theme.Warning // Scale and start/restart actions
+		textColor = m.theme.Warning
 	} else {
-		borderColor = lipgloss.Color("1")  // Red for delete actions
-		textColor = lipgloss.Color("1")    // Red text
+		borderColor = m.theme.Danger // Delete and stop actions
+		textColor = m.theme.Danger
 	}
 
 	// Dialog box style
@@ -1104,7 +3402,7 @@ func (m Model) overlayConfirmDialog(baseView string) string {
 
 	// Prompt style
 	promptStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(m.theme.Muted).
 		Align(lipgloss.Center)
 
 	dialogContent := lipgloss.JoinVertical(
@@ -1131,16 +3429,16 @@ func (m Model) overlayConfirmDialog(baseView string) string {
 
 // renderSection renders a single left pane section with border and title
 func (m Model) renderSection(sectionList list.Model, title string, isFocused bool) string {
-	borderColor := lipgloss.Color("240")
+	borderColor := m.theme.Border
 	if isFocused {
-		borderColor = lipgloss.Color("6")
+		borderColor = m.theme.Accent
 	}
 
 	// Get the list content
 	listContent := sectionList.View()
 
 	// Calculate width and build custom border with embedded title
-	width := m.width/3 - 2
+	width := m.leftPaneWidth() - 2
 	titleLen := len(title)
 
 	// Build top border with embedded title: ─── [4] Services ───
@@ -1191,7 +3489,7 @@ func (m Model) renderRightPane() string {
 	tabStyle := lipgloss.NewStyle().Padding(0, 1)
 	activeTabStyle := lipgloss.NewStyle().
 		Bold(true).
-		Foreground(lipgloss.Color("6")).
+		Foreground(m.theme.Accent).
 		Padding(0, 1)
 
 	tabs := []string{"Logs", "Stats", "Env", "Config", "Top", "Exec"}
@@ -1211,10 +3509,20 @@ func (m Model) renderRightPane() string {
 	var content string
 	switch m.activeTab {
 	case LogsTab:
-		if m.selectedResource != "" {
-			content = m.logsViewport.View()
+		if m.selectedResourceType == "unit" {
+			viewer := m.logViewer
+			viewer.SetTitle(m.logsTitle(m.journalHeader()))
+			content = viewer.View()
+		} else if m.selectedResourceType == "pod" {
+			viewer := m.logViewer
+			viewer.SetTitle(m.logsTitle(m.podLogsTitle()))
+			content = viewer.View()
+		} else if m.selectedResourceType == "deployment" {
+			viewer := m.logViewer
+			viewer.SetTitle(m.logsTitle(m.deploymentLogsTitle()))
+			content = viewer.View()
 		} else {
-			content = "Select a pod to view logs"
+			content = "Select a pod or deployment to view logs"
 		}
 	case StatsTab:
 		content = m.statsViewport.View()
@@ -1231,7 +3539,7 @@ func (m Model) renderRightPane() string {
 			content = "Select a resource to view YAML configuration"
 		}
 	case TopTab:
-		content = "Top/Resource usage (coming soon)"
+		content = m.topViewport.View()
 	case ExecTab:
 		content = m.renderExec()
 	}
@@ -1248,44 +3556,378 @@ func (m Model) renderStats() string {
 		return fmt.Sprintf("Loading metrics for %s...\n\nNote: Metrics require metrics-server to be installed in the cluster.\nInstall with: kubectl apply -f https://github.com/kubernetes-sigs/metrics-server/releases/latest/download/components.yaml", m.selectedResource)
 	}
 
-	// Calculate percentage bars (assume some reasonable limits)
-	// CPU: assume 1000m (1 core) as 100%
-	cpuPercent := (m.currentMetrics.CPUUsageMillis * 100) / 1000
-	if cpuPercent > 100 {
-		cpuPercent = 100
+	metrics := m.currentMetrics
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	warnStyle := lipgloss.NewStyle().Foreground(m.theme.Warning)
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("Resource Metrics for: %s", metrics.Name)) + "\n\n")
+
+	b.WriteString(headerStyle.Render("Pod total") + "\n")
+	b.WriteString(m.resourceLine("CPU", metrics.CPUUsageMillis, metrics.CPURequestMillis, metrics.CPULimitMillis, k8s.FormatCPU) + "\n")
+	b.WriteString(m.resourceLine("Memory", metrics.MemoryUsageBytes, metrics.MemRequestBytes, metrics.MemLimitBytes, k8s.FormatMemory) + "\n")
+	if h, ok := m.history[historyKey("pod", metrics.Namespace, metrics.Name)]; ok {
+		b.WriteString(m.historyLines(h, metrics.CPULimitMillis, metrics.MemLimitBytes, m.cfg.Kubernetes.RefreshInterval()))
 	}
 
-	// Memory: assume 1Gi as 100%
-	memPercent := (m.currentMetrics.MemoryUsageBytes * 100) / (1024 * 1024 * 1024)
-	if memPercent > 100 {
-		memPercent = 100
+	for _, c := range metrics.Containers {
+		title := headerStyle.Render("Container: " + c.Name)
+		var missing []string
+		if c.CPULimitMillis == 0 {
+			missing = append(missing, "CPU")
+		}
+		if c.MemLimitBytes == 0 {
+			missing = append(missing, "memory")
+		}
+		if len(missing) > 0 {
+			title += "  " + warnStyle.Render(fmt.Sprintf("⚠ no %s limit", strings.Join(missing, " or ")))
+		}
+
+		b.WriteString("\n" + title + "\n")
+		b.WriteString(m.resourceLine("CPU", c.CPUMillis, c.CPURequestMillis, c.CPULimitMillis, k8s.FormatCPU) + "\n")
+		b.WriteString(m.resourceLine("Memory", c.MemoryBytes, c.MemRequestBytes, c.MemLimitBytes, k8s.FormatMemory) + "\n")
 	}
 
-	// Create visual bars
-	cpuBar := createBar(int(cpuPercent), 20)
-	memBar := createBar(int(memPercent), 20)
+	b.WriteString(fmt.Sprintf("\nNamespace: %s", metrics.Namespace))
+	return b.String()
+}
 
-	return fmt.Sprintf(`Resource Metrics for: %s
+// renderUnitStats renders the selected unit's cgroup usage and its history
+func (m Model) renderUnitStats() string {
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	title := headerStyle.Render(fmt.Sprintf("Resource usage for: %s", m.selectedResource)) + "\n\n"
 
-CPU Usage:    %s  %s (%d%%)
-Memory Usage: %s  %s (%d%%)
+	h, ok := m.history[historyKey("unit", "", m.selectedResource)]
+	if !ok {
+		return title + "Collecting usage...\n\nNote: usage comes from the unit's cgroup accounting, which only some unit types have."
+	}
 
-Raw Values:
-  CPU:    %s
-  Memory: %s
+	mem := h.mem.Values()
+	var b strings.Builder
+	b.WriteString(title)
+	if cpu := h.cpu.Values(); len(cpu) > 0 {
+		b.WriteString(fmt.Sprintf("  %-7s %8s\n", "CPU:", k8s.FormatCPU(cpu[len(cpu)-1])))
+	}
+	if len(mem) > 0 {
+		line := fmt.Sprintf("  %-7s %8s", "Memory:", k8s.FormatMemory(mem[len(mem)-1]))
+		if h.memLimit > 0 {
+			line += fmt.Sprintf("  %s of MemoryMax %s", m.usagePercent(mem[len(mem)-1], h.memLimit, 0), k8s.FormatMemory(h.memLimit))
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(m.historyLines(h, 0, h.memLimit, m.cfg.Systemd.RefreshInterval()))
+	return b.String()
+}
 
-Namespace: %s`,
-		m.currentMetrics.Name,
-		m.currentMetrics.CPUUsageFormatted,
-		cpuBar,
-		cpuPercent,
-		m.currentMetrics.MemoryUsageFormatted,
-		memBar,
-		memPercent,
-		m.currentMetrics.CPUUsageFormatted,
-		m.currentMetrics.MemoryUsageFormatted,
-		m.currentMetrics.Namespace,
-	)
+// historyLines renders CPU and memory sparklines, scaled to the limits when
+// set. interval is the time between samples.
+func (m Model) historyLines(h *usageHistory, cpuLimit, memLimit int64, interval time.Duration) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	legend := func(limit int64) string {
+		scale := "scaled to the peak"
+		if limit > 0 {
+			scale = "scaled to the limit"
+		}
+		return mutedStyle.Render(fmt.Sprintf("last %s, %s", historyWindow(interval), scale))
+	}
+
+	return fmt.Sprintf("  %-7s %s  %s\n  %-7s %s  %s\n",
+		"CPU~", sparkline(h.cpu.Values(), cpuLimit, historySamples), legend(cpuLimit),
+		"Mem~", sparkline(h.mem.Values(), memLimit, historySamples), legend(memLimit))
+}
+
+// resourceLine renders usage with a bar against the limit, or the request
+// when there's no limit, followed by the percentage of each that is set
+func (m Model) resourceLine(label string, used, request, limit int64, format func(int64) string) string {
+	basis := limit
+	if basis == 0 {
+		basis = request
+	}
+
+	bar := strings.Repeat(" ", 20)
+	if basis > 0 {
+		bar = createBar(int(used*100/basis), 20)
+	}
+
+	var parts []string
+	if limit > 0 {
+		parts = append(parts, fmt.Sprintf("%s of limit %s", m.usagePercent(used, limit, 0), format(limit)))
+	} else {
+		parts = append(parts, lipgloss.NewStyle().Foreground(m.theme.Warning).Render("no limit"))
+	}
+	if request > 0 {
+		parts = append(parts, fmt.Sprintf("%s of request %s", m.usagePercent(used, request, 0), format(request)))
+	} else {
+		parts = append(parts, "no request")
+	}
+
+	return fmt.Sprintf("  %-7s %8s  %s  %s", label+":", format(used), bar, strings.Join(parts, " • "))
+}
+
+// topSortKey is the column the Top tab is sorted by
+type topSortKey int
+
+const (
+	topSortCPU topSortKey = iota
+	topSortMemory
+	topSortName
+)
+
+// topColumnsWidth is the width of the Top tab's pod columns after the name
+const topColumnsWidth = 98
+
+// topSparkWidth is the width of the Top tab's history sparklines
+const topSparkWidth = 10
+
+// loadTop loads pod usage, and node usage while the Top tab is shown
+func (m Model) loadTop() tea.Cmd {
+	mgr := m.k8sManager
+	withNodes := m.activeTab == TopTab
+	return func() tea.Msg {
+		if mgr == nil {
+			err := fmt.Errorf("k8s manager not initialized")
+			return topLoadedMsg{podsErr: err, nodesErr: err, withNodes: withNodes}
+		}
+		msg := topLoadedMsg{namespace: mgr.GetNamespace(), withNodes: withNodes}
+		msg.pods, msg.podsErr = mgr.ListPodUsage()
+		if withNodes {
+			msg.nodes, msg.nodesErr = mgr.ListNodeUsage()
+		}
+		return msg
+	}
+}
+
+// sampledUnits lists the units whose usage history is kept: the watched
+// units, or just the selected one when every service is listed
+func (m Model) sampledUnits() []string {
+	names := append([]string(nil), m.unitsToWatch...)
+	if m.selectedResourceType == "unit" && !slices.Contains(names, m.selectedResource) {
+		names = append(names, m.selectedResource)
+	}
+	return names
+}
+
+// loadUnitUsage samples the cgroup usage of units
+func (m Model) loadUnitUsage(names []string) tea.Cmd {
+	mgr := m.systemdManager
+	if mgr == nil || len(names) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := unitUsageLoadedMsg{at: time.Now(), usage: make(map[string]*systemd.UnitUsage, len(names))}
+		for _, name := range names {
+			// Units without a cgroup or accounting are left out
+			if usage, err := mgr.GetUnitUsage(name); err == nil {
+				msg.usage[name] = usage
+			}
+		}
+		return msg
+	}
+}
+
+// historyFor returns the usage history under key, creating it if needed
+func (m *Model) historyFor(key string) *usageHistory {
+	h, ok := m.history[key]
+	if !ok {
+		h = &usageHistory{}
+		m.history[key] = h
+	}
+	return h
+}
+
+// recordPodUsage adds a sample for every pod and forgets pods that are gone
+func (m *Model) recordPodUsage(namespace string, pods []k8s.PodUsage) {
+	seen := make(map[string]bool, len(pods))
+	for _, p := range pods {
+		key := historyKey("pod", namespace, p.Name)
+		seen[key] = true
+		h := m.historyFor(key)
+		h.cpu.Add(p.CPUMillis)
+		h.mem.Add(p.MemoryBytes)
+	}
+	for key := range m.history {
+		if strings.HasPrefix(key, "pod/") && !seen[key] {
+			delete(m.history, key)
+		}
+	}
+}
+
+// recordUnitUsage adds a sample for each unit. CPU is recorded as the
+// average rate since the unit's previous sample, in millicores.
+func (m *Model) recordUnitUsage(at time.Time, usage map[string]*systemd.UnitUsage) {
+	for name, u := range usage {
+		h := m.historyFor(historyKey("unit", "", name))
+		if !h.lastAt.IsZero() && u.CPUNanos >= h.lastCPUNanos && at.After(h.lastAt) {
+			elapsed := at.Sub(h.lastAt).Nanoseconds()
+			h.cpu.Add(int64(u.CPUNanos-h.lastCPUNanos) * 1000 / elapsed)
+		}
+		h.lastCPUNanos, h.lastAt = u.CPUNanos, at
+		h.mem.Add(int64(u.MemoryBytes))
+		h.memLimit = int64(u.MemoryMax)
+	}
+}
+
+// historyWindow describes how far back a full sparkline reaches
+func historyWindow(interval time.Duration) string {
+	return (interval * historySamples).String()
+}
+
+// renderTop renders node usage against allocatable capacity, then every
+// pod's usage against its requests and limits
+func (m Model) renderTop() string {
+	if !m.topLoaded {
+		return "Loading resource usage..."
+	}
+
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	sortNames := map[topSortKey]string{topSortCPU: "CPU", topSortMemory: "memory", topSortName: "name"}
+	dir := "↓"
+	if m.topSortAsc {
+		dir = "↑"
+	}
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("Resource usage in %s", m.currentNamespace)))
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("  sorted by %s %s • C: CPU • M: memory • N: name", sortNames[m.topSort], dir)))
+	b.WriteString("\n\n")
+
+	// Nodes
+	if m.topNodesErr != nil {
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("Nodes unavailable: %v", m.topNodesErr)) + "\n")
+	} else {
+		nodes := append([]k8s.NodeUsage(nil), m.topNodes...)
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return topLess(m.topSort, m.topSortAsc,
+				topRow{nodes[i].Name, nodes[i].CPUMillis, nodes[i].MemoryBytes},
+				topRow{nodes[j].Name, nodes[j].CPUMillis, nodes[j].MemoryBytes})
+		})
+
+		nameWidth := topNameWidth(len("NODE"), len(nodes), func(i int) string { return nodes[i].Name }, 0)
+		b.WriteString(headerStyle.Render(fmt.Sprintf("%-*s %7s %5s %8s %5s", nameWidth, "NODE", "CPU", "%CPU", "MEM", "%MEM")) + "\n")
+		for _, n := range nodes {
+			fmt.Fprintf(&b, "%-*s %7s %s %8s %s\n",
+				nameWidth, truncateText(n.Name, nameWidth),
+				k8s.FormatCPU(n.CPUMillis), m.usagePercent(n.CPUMillis, n.CPUAllocatableMillis, 5),
+				k8s.FormatMemory(n.MemoryBytes), m.usagePercent(n.MemoryBytes, n.MemAllocatableBytes, 5))
+		}
+	}
+	b.WriteString("\n")
+
+	// Pods
+	if m.topPodsErr != nil {
+		b.WriteString(fmt.Sprintf("Metrics unavailable: %v\n\nNote: Metrics require metrics-server to be installed in the cluster.", m.topPodsErr))
+		return b.String()
+	}
+	if len(m.topPods) == 0 {
+		b.WriteString(mutedStyle.Render("No pod metrics in this namespace"))
+		return b.String()
+	}
+
+	pods := append([]k8s.PodUsage(nil), m.topPods...)
+	sort.SliceStable(pods, func(i, j int) bool {
+		return topLess(m.topSort, m.topSortAsc,
+			topRow{pods[i].Name, pods[i].CPUMillis, pods[i].MemoryBytes},
+			topRow{pods[j].Name, pods[j].CPUMillis, pods[j].MemoryBytes})
+	})
+
+	nameWidth := topNameWidth(len("POD"), len(pods), func(i int) string { return pods[i].Name }, m.topViewport.Width-topColumnsWidth-2)
+	b.WriteString(headerStyle.Render(fmt.Sprintf("%-*s %7s %7s %5s %7s %5s %8s %8s %5s %8s %5s %-*s %-*s %s",
+		nameWidth, "POD", "CPU", "REQ", "%REQ", "LIM", "%LIM", "MEM", "REQ", "%REQ", "LIM", "%LIM",
+		topSparkWidth, "CPU HIST", topSparkWidth, "MEM HIST", "NODE")) + "\n")
+	for _, p := range pods {
+		cpuSpark, memSpark := strings.Repeat(" ", topSparkWidth), strings.Repeat(" ", topSparkWidth)
+		if h, ok := m.history[historyKey("pod", m.currentNamespace, p.Name)]; ok {
+			cpuSpark = sparkline(h.cpu.Values(), p.CPULimitMillis, topSparkWidth)
+			memSpark = sparkline(h.mem.Values(), p.MemLimitBytes, topSparkWidth)
+		}
+		fmt.Fprintf(&b, "%-*s %7s %7s %s %7s %s %8s %8s %s %8s %s %s %s %s\n",
+			nameWidth, truncateText(p.Name, nameWidth),
+			k8s.FormatCPU(p.CPUMillis),
+			optionalQuantity(p.CPURequestMillis, k8s.FormatCPU), m.usagePercent(p.CPUMillis, p.CPURequestMillis, 5),
+			optionalQuantity(p.CPULimitMillis, k8s.FormatCPU), m.usagePercent(p.CPUMillis, p.CPULimitMillis, 5),
+			k8s.FormatMemory(p.MemoryBytes),
+			optionalQuantity(p.MemRequestBytes, k8s.FormatMemory), m.usagePercent(p.MemoryBytes, p.MemRequestBytes, 5),
+			optionalQuantity(p.MemLimitBytes, k8s.FormatMemory), m.usagePercent(p.MemoryBytes, p.MemLimitBytes, 5),
+			cpuSpark, memSpark,
+			mutedStyle.Render(p.Node))
+	}
+	return b.String()
+}
+
+// topRow is what Top tab rows are sorted on
+type topRow struct {
+	name     string
+	cpu, mem int64
+}
+
+// topLess orders Top tab rows by the sort key, breaking ties by name so
+// rows don't jump around between refreshes
+func topLess(key topSortKey, asc bool, a, b topRow) bool {
+	var x, y int64
+	switch key {
+	case topSortCPU:
+		x, y = a.cpu, b.cpu
+	case topSortMemory:
+		x, y = a.mem, b.mem
+	}
+	if x == y {
+		if key == topSortName && !asc {
+			return a.name > b.name
+		}
+		return a.name < b.name
+	}
+	if asc {
+		return x < y
+	}
+	return x > y
+}
+
+// topNameWidth sizes a name column to its longest name, within limit if set
+func topNameWidth(minWidth, n int, name func(i int) string, limit int) int {
+	width := minWidth
+	for i := 0; i < n; i++ {
+		width = max(width, len(name(i)))
+	}
+	if limit > 0 && width > limit {
+		width = max(limit, minWidth)
+	}
+	return width
+}
+
+// usagePercent renders used as a percentage of total padded to width,
+// colored as it nears 100%, or "-" when total isn't set
+func (m Model) usagePercent(used, total int64, width int) string {
+	if total <= 0 {
+		return fmt.Sprintf("%*s", width, "-")
+	}
+	percent := used * 100 / total
+	text := fmt.Sprintf("%*s", width, fmt.Sprintf("%d%%", percent))
+	switch {
+	case percent >= 90:
+		return lipgloss.NewStyle().Foreground(m.theme.Danger).Render(text)
+	case percent >= 70:
+		return lipgloss.NewStyle().Foreground(m.theme.Warning).Render(text)
+	}
+	return text
+}
+
+// optionalQuantity formats a request or limit, or "-" when it isn't set
+func optionalQuantity(value int64, format func(int64) string) string {
+	if value <= 0 {
+		return "-"
+	}
+	return format(value)
+}
+
+// truncateText shortens text to width runes, marking the cut with "…"
+func truncateText(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width || width < 1 {
+		return text
+	}
+	return string(runes[:width-1]) + "…"
 }
 
 // createBar creates a visual progress bar
@@ -1311,69 +3953,231 @@ func createBar(percent int, width int) string {
 	return bar
 }
 
-func (m Model) renderEnv() string {
+// envRef is a variable of one of the selected pod's containers
+type envRef struct {
+	container string
+	env       k8s.EnvVar
+}
+
+// key identifies the variable in Model.envRevealed
+func (r envRef) key() string {
+	return r.container + "/" + r.env.Name
+}
+
+// envVarList lists the selected pod's variables in display order
+func (m Model) envVarList() []envRef {
+	if m.currentEnvVars == nil {
+		return nil
+	}
+	var refs []envRef
+	for _, name := range m.currentEnvVars.ContainerNames {
+		for _, env := range m.currentEnvVars.Containers[name] {
+			refs = append(refs, envRef{container: name, env: env})
+		}
+	}
+	return refs
+}
+
+// refreshEnv re-renders the Env tab, scrolling the selected variable into view
+func (m *Model) refreshEnv() {
+	content, line := m.renderEnv()
+	m.envViewport.SetContent(content)
+	if line < m.envViewport.YOffset {
+		m.envViewport.SetYOffset(line)
+	} else if bottom := line + 4; bottom > m.envViewport.YOffset+m.envViewport.Height {
+		m.envViewport.SetYOffset(bottom - m.envViewport.Height)
+	}
+}
+
+// renderEnv renders the selected pod's variables and returns the line the
+// selected one starts on
+func (m Model) renderEnv() (string, int) {
 	if m.selectedResource == "" {
-		return "Select a pod to view environment variables"
+		return "Select a pod to view environment variables", 0
 	}
 
 	if m.currentEnvVars == nil {
-		return fmt.Sprintf("Loading environment variables for %s...", m.selectedResource)
+		return fmt.Sprintf("Loading environment variables for %s...", m.selectedResource), 0
 	}
 
 	if len(m.currentEnvVars.Containers) == 0 {
-		return fmt.Sprintf("No environment variables found for %s", m.currentEnvVars.PodName)
+		return fmt.Sprintf("No environment variables found for %s", m.currentEnvVars.PodName), 0
 	}
 
-	var output string
-	output += fmt.Sprintf("Environment Variables for: %s\n", m.currentEnvVars.PodName)
-	output += fmt.Sprintf("Namespace: %s\n\n", m.currentEnvVars.Namespace)
+	selectedStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+	warningStyle := lipgloss.NewStyle().Foreground(m.theme.Warning)
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Environment Variables for: %s\n", m.currentEnvVars.PodName))
+	b.WriteString(fmt.Sprintf("Namespace: %s\n", m.currentEnvVars.Namespace))
+	b.WriteString(mutedStyle.Render("[/]: select variable, V: reveal secret") + "\n\n")
 
-	// Iterate through each container
-	for containerName, envVars := range m.currentEnvVars.Containers {
-		output += fmt.Sprintf("━━━ Container: %s ━━━\n\n", containerName)
+	refs := m.envVarList()
+	selectedLine, i := 0, 0
+	for _, containerName := range m.currentEnvVars.ContainerNames {
+		b.WriteString(fmt.Sprintf("━━━ Container: %s ━━━\n\n", containerName))
 
-		if len(envVars) == 0 {
-			output += "  (no environment variables)\n\n"
+		if len(m.currentEnvVars.Containers[containerName]) == 0 {
+			b.WriteString("  (no environment variables)\n\n")
 			continue
 		}
 
-		for _, env := range envVars {
-			// Show env var name
-			output += fmt.Sprintf("  %s\n", env.Name)
+		for ; i < len(refs) && refs[i].container == containerName; i++ {
+			ref := refs[i]
+			if i == m.envCursor {
+				selectedLine = strings.Count(b.String(), "\n")
+				b.WriteString(selectedStyle.Render("▶ "+ref.env.Name) + "\n")
+			} else {
+				b.WriteString(fmt.Sprintf("  %s\n", ref.env.Name))
+			}
 
-			// Show value or source
-			if env.Value != "" {
-				output += fmt.Sprintf("    = %s\n", env.Value)
+			switch {
+			case ref.env.Error != "":
+				b.WriteString(warningStyle.Render("    ⚠ "+ref.env.Error) + "\n")
+			case ref.env.Secret && !m.envRevealed[ref.key()]:
+				b.WriteString("    = " + mutedStyle.Render("•••••••• (hidden)") + "\n")
+			case ref.env.Value != "" || ref.env.ValueFrom != "":
+				b.WriteString(fmt.Sprintf("    = %s\n", ref.env.Value))
 			}
-			if env.ValueFrom != "" {
-				output += fmt.Sprintf("    → %s\n", env.ValueFrom)
+			if ref.env.ValueFrom != "" {
+				b.WriteString(mutedStyle.Render(fmt.Sprintf("    → %s", ref.env.ValueFrom)) + "\n")
 			}
-			output += "\n"
+			b.WriteString("\n")
 		}
 	}
 
-	return output
+	return b.String(), selectedLine
+}
+
+// showingResourceYAML reports whether the Config tab shows a resource's YAML
+func (m Model) showingResourceYAML() bool {
+	switch m.selectedResourceType {
+	case "pod", "deployment", "service":
+		return m.currentYAML != "" && !m.reviewingYAMLEdit()
+	}
+	return false
+}
+
+// refreshConfig re-renders the Config tab, scrolling the selected YAML
+// section into view
+func (m *Model) refreshConfig() {
+	content, line := m.renderConfig()
+	m.configViewport.SetContent(content)
+	if line < m.configViewport.YOffset {
+		m.configViewport.SetYOffset(line)
+	} else if line >= m.configViewport.YOffset+m.configViewport.Height {
+		m.configViewport.SetYOffset(line - m.configViewport.Height + 1)
+	}
 }
 
-func (m Model) renderConfig() string {
+// renderConfig renders the Config tab and returns the line of the selected
+// YAML section
+func (m Model) renderConfig() (string, int) {
 	if m.selectedResource == "" {
-		return "Select a resource to view YAML configuration"
+		return "Select a resource to view YAML configuration", 0
+	}
+
+	if m.reviewingYAMLEdit() {
+		return m.renderYAMLEdit(), 0
 	}
 
 	if m.currentYAML == "" {
-		return fmt.Sprintf("Loading YAML for %s...", m.selectedResource)
+		return fmt.Sprintf("Loading YAML for %s...", m.selectedResource), 0
 	}
 
 	// Display the YAML with a header
-	header := fmt.Sprintf("YAML Configuration for %s: %s (Namespace: %s)\n\n",
-		m.selectedResourceType, m.selectedResource, m.currentNamespace)
+	header := fmt.Sprintf("YAML Configuration for %s: %s (Namespace: %s)\n", m.selectedResourceType, m.selectedResource, m.currentNamespace)
+	status := "h: show status"
+	if m.configYAML.showStatus {
+		status = "h: hide status"
+	}
+	header += lipgloss.NewStyle().Foreground(m.theme.Muted).Render(
+		"[/]: select section, z: fold, Z: fold all, "+status+", E: edit") + "\n\n"
 
-	return header + m.currentYAML
+	content, line := m.configYAML.Render(m.theme)
+	return header + content, strings.Count(header, "\n") + line
 }
 
-func (m Model) renderExec() string {
+// renderYAMLEdit renders the diff a YAML edit makes and whether it can be
+// applied
+func (m Model) renderYAMLEdit() string {
+	edit := m.yamlEdit
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(m.theme.Accent)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.Muted)
+
+	var b strings.Builder
+	b.WriteString(headerStyle.Render(fmt.Sprintf("Review changes to %s: %s (Namespace: %s)", edit.resourceType, edit.name, m.currentNamespace)) + "\n")
+	switch {
+	case edit.conflict:
+		b.WriteString(lipgloss.NewStyle().Foreground(m.theme.Warning).Render(
+			fmt.Sprintf("⚠ %v\nApplying forces the change and takes ownership of these fields.", edit.err)) + "\n")
+		b.WriteString(mutedStyle.Render("y: force apply, E: edit again, n: discard") + "\n\n")
+	case edit.err != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(m.theme.Danger).Render(fmt.Sprintf("✗ %v", edit.err)) + "\n")
+		b.WriteString(mutedStyle.Render("E: edit again, n: discard") + "\n\n")
+	default:
+		b.WriteString(mutedStyle.Render("Dry run passed. y: apply, E: edit again, n: discard") + "\n\n")
+	}
+
+	switch {
+	case edit.diff != "":
+		b.WriteString(renderDiff(edit.diff, m.theme))
+	case edit.err == nil:
+		b.WriteString("The edit doesn't change the live object.")
+	}
+	return b.String()
+}
+
+func (m Model) renderUnitStatus() string {
 	if m.selectedResource == "" {
+		return "Select a unit to view its status"
+	}
+
+	if m.currentUnitStatus == nil {
+		return fmt.Sprintf("Loading status for %s...", m.selectedResource)
+	}
+
+	s := m.currentUnitStatus
+	output := fmt.Sprintf("%s %s - %s\n\n", unitStateIcon(s.ActiveState), s.Name, s.Description)
+	output += fmt.Sprintf("  Loaded:  %s (%s; %s)\n", s.LoadState, s.FragmentPath, s.UnitFileState)
+
+	active := fmt.Sprintf("%s (%s)", s.ActiveState, s.SubState)
+	if !s.ActiveSince.IsZero() {
+		active += fmt.Sprintf(" since %s", s.ActiveSince.Format(time.RFC1123))
+	}
+	output += fmt.Sprintf("  Active:  %s\n", active)
+
+	if s.MainPID != 0 {
+		output += fmt.Sprintf("  Main PID: %d\n", s.MainPID)
+	}
+
+	return output
+}
+
+func (m Model) renderExec() string {
+	if m.selectedResource == "" || m.selectedResourceType != "pod" {
 		return "Select a pod to exec into"
 	}
-	return fmt.Sprintf("Exec into: %s\n\n$ kubectl exec -it %s -n %s -- /bin/sh\n\n(Coming soon: direct shell access)", m.selectedResource, m.selectedResource, m.currentNamespace)
+
+	output := fmt.Sprintf("Exec into: %s (Namespace: %s)\n\n", m.selectedResource, m.currentNamespace)
+
+	if len(m.execContainers) == 0 {
+		output += "Loading containers...\n"
+	} else {
+		output += "Container:\n"
+		selectedStyle := lipgloss.NewStyle().Foreground(m.theme.Accent).Bold(true)
+		for i, name := range m.execContainers {
+			if i == m.execContainerIdx {
+				output += selectedStyle.Render("  > "+name) + "\n"
+			} else {
+				output += "    " + name + "\n"
+			}
+		}
+	}
+
+	output += fmt.Sprintf("\nShells: %s\n\n", strings.Join(m.cfg.Kubernetes.ExecShells, " → "))
+	output += "i: open shell • [/]: change container • ctrl+]: detach from shell"
+
+	return output
 }
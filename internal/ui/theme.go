@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme holds the colors used throughout the UI
+type Theme struct {
+	Accent  lipgloss.Color // titles, focused borders and the selected item
+	Border  lipgloss.Color // unfocused borders
+	Muted   lipgloss.Color // help and secondary text
+	Warning lipgloss.Color // scale and start/restart confirmations
+	Danger  lipgloss.Color // delete and stop confirmations
+}
+
+// themes are the built-in themes selectable with ui.theme
+var themes = map[string]Theme{
+	"default": {
+		Accent:  lipgloss.Color("6"),
+		Border:  lipgloss.Color("240"),
+		Muted:   lipgloss.Color("241"),
+		Warning: lipgloss.Color("3"),
+		Danger:  lipgloss.Color("1"),
+	},
+	"monokai": {
+		Accent:  lipgloss.Color("#A6E22E"),
+		Border:  lipgloss.Color("#49483E"),
+		Muted:   lipgloss.Color("#75715E"),
+		Warning: lipgloss.Color("#E6DB74"),
+		Danger:  lipgloss.Color("#F92672"),
+	},
+	"dracula": {
+		Accent:  lipgloss.Color("#BD93F9"),
+		Border:  lipgloss.Color("#44475A"),
+		Muted:   lipgloss.Color("#6272A4"),
+		Warning: lipgloss.Color("#F1FA8C"),
+		Danger:  lipgloss.Color("#FF5555"),
+	},
+}
+
+// LookupTheme returns the named theme, or an error listing the valid names
+func LookupTheme(name string) (Theme, error) {
+	if theme, ok := themes[name]; ok {
+		return theme, nil
+	}
+
+	names := make([]string, 0, len(themes))
+	for n := range themes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return Theme{}, fmt.Errorf("unknown ui.theme %q: must be one of %s", name, strings.Join(names, ", "))
+}
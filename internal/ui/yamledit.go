@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// yamlEdit is an edit of a resource's YAML, from the editor to the apply
+type yamlEdit struct {
+	resourceType string
+	name         string
+	path         string // temporary file the editor works on
+	original     string // the file's content before the editor last opened it
+	content      string // the edited YAML
+	diff         string // unified diff of the live object and the dry-run result
+	err          error  // why the dry run or apply failed
+	conflict     bool   // err is a field manager conflict, which forcing overrides
+}
+
+// newYAMLEdit writes a resource's YAML to a temporary file for editing
+func newYAMLEdit(resourceType, name, content string) (*yamlEdit, error) {
+	f, err := os.CreateTemp("", fmt.Sprintf("lazystack-%s-%s-*.yaml", resourceType, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		os.Remove(f.Name())
+		return nil, fmt.Errorf("failed to write %s: %w", f.Name(), err)
+	}
+	return &yamlEdit{resourceType: resourceType, name: name, path: f.Name(), original: content}, nil
+}
+
+// editorCommand runs $VISUAL or $EDITOR, falling back to vi, on path.
+// The variable may include arguments, such as "code --wait".
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...)
+}
+
+// yamlDiff returns a unified diff from live to edited, empty when they match
+func yamlDiff(name, live, edited string) string {
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(live),
+		B:        difflib.SplitLines(edited),
+		FromFile: name + " (live)",
+		ToFile:   name + " (edited)",
+		Context:  3,
+	})
+	return diff
+}
+
+// renderDiff colors a unified diff's added, removed and hunk header lines
+func renderDiff(diff string, theme Theme) string {
+	added := lipgloss.NewStyle().Foreground(theme.Accent)
+	removed := lipgloss.NewStyle().Foreground(theme.Danger)
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "@@"):
+			lines[i] = muted.Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = added.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = removed.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
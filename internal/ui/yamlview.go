@@ -0,0 +1,299 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// yamlView shows YAML with syntax highlighting and collapsible sections.
+// A section is a key or list item together with the lines nested under it.
+type yamlView struct {
+	lines      []string
+	end        []int  // index just past each line's section
+	scalar     []bool // line is part of a multi-line string
+	collapsed  map[int]bool
+	cursor     int // line of the selected section
+	showStatus bool
+}
+
+// SetContent replaces the YAML, unfolding everything
+func (v *yamlView) SetContent(content string) {
+	v.lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	v.end = make([]int, len(v.lines))
+	v.scalar = make([]bool, len(v.lines))
+	v.collapsed = make(map[int]bool)
+
+	scalarIndent := -1
+	for i, line := range v.lines {
+		indent := yamlIndent(line)
+		if scalarIndent >= 0 && (indent > scalarIndent || strings.TrimSpace(line) == "") {
+			v.scalar[i] = true
+			continue
+		}
+		scalarIndent = -1
+		if _, value, _ := splitYAMLLine(line); isBlockIndicator(value) {
+			scalarIndent = indent
+		}
+	}
+
+	for i, line := range v.lines {
+		v.end[i] = i + 1
+		if v.scalar[i] {
+			continue
+		}
+		// Lists aren't indented under their key, so a key's section also
+		// takes the items at its own indent
+		indent, item := yamlIndent(line), isYAMLItem(line)
+		for v.end[i] < len(v.lines) {
+			next := v.lines[v.end[i]]
+			if n := yamlIndent(next); n > indent || (n == indent && !item && isYAMLItem(next)) {
+				v.end[i]++
+				continue
+			}
+			break
+		}
+	}
+
+	v.cursor = 0
+	if !v.foldable(0) {
+		v.Move(1)
+	}
+}
+
+// foldable reports whether line i starts a section with lines under it
+func (v *yamlView) foldable(i int) bool {
+	return i < len(v.lines) && !v.scalar[i] && v.end[i] > i+1
+}
+
+// statusSection returns the bounds of the top-level status section, if any
+func (v *yamlView) statusSection() (int, int, bool) {
+	for i, line := range v.lines {
+		if line == "status:" {
+			return i, v.end[i], true
+		}
+	}
+	return 0, 0, false
+}
+
+// visible lists the lines shown, skipping folded sections and the status
+// section when it's hidden
+func (v *yamlView) visible() []int {
+	start, end, hasStatus := v.statusSection()
+	hideStatus := hasStatus && !v.showStatus
+
+	var rows []int
+	for i := 0; i < len(v.lines); i++ {
+		if hideStatus && i == start {
+			i = end - 1
+			continue
+		}
+		rows = append(rows, i)
+		if v.collapsed[i] {
+			i = v.end[i] - 1
+		}
+	}
+	return rows
+}
+
+// Move selects the next or previous visible section
+func (v *yamlView) Move(step int) {
+	rows := v.visible()
+	pos := -1
+	for j, i := range rows {
+		if i == v.cursor {
+			pos = j
+		}
+	}
+	for j := pos + step; j >= 0 && j < len(rows); j += step {
+		if v.foldable(rows[j]) {
+			v.cursor = rows[j]
+			return
+		}
+	}
+}
+
+// Toggle folds or unfolds the selected section
+func (v *yamlView) Toggle() {
+	if v.collapsed[v.cursor] {
+		delete(v.collapsed, v.cursor)
+	} else if v.foldable(v.cursor) {
+		v.collapsed[v.cursor] = true
+	}
+}
+
+// ToggleAll folds every top-level section, or unfolds everything if any
+// section is folded
+func (v *yamlView) ToggleAll() {
+	if len(v.collapsed) > 0 {
+		clear(v.collapsed)
+		return
+	}
+	for i, line := range v.lines {
+		if yamlIndent(line) == 0 && !isYAMLItem(line) && v.foldable(i) {
+			v.collapsed[i] = true
+		}
+	}
+	// Keep the selection on a line that is still shown
+	for i := range v.collapsed {
+		if v.cursor > i && v.cursor < v.end[i] {
+			v.cursor = i
+		}
+	}
+}
+
+// ToggleStatus shows or hides the status section
+func (v *yamlView) ToggleStatus() {
+	v.showStatus = !v.showStatus
+	if start, end, ok := v.statusSection(); ok && !v.showStatus && v.cursor >= start && v.cursor < end {
+		v.cursor = 0
+		if !v.foldable(0) {
+			v.Move(1)
+		}
+	}
+}
+
+// Render highlights the visible lines, marking the selected and folded
+// sections in a gutter, and returns the row of the selected section
+func (v *yamlView) Render(theme Theme) (string, int) {
+	cursorStyle := lipgloss.NewStyle().Bold(true).Foreground(theme.Accent)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	var b strings.Builder
+	cursorRow := 0
+	for row, i := range v.visible() {
+		switch {
+		case i == v.cursor:
+			cursorRow = row
+			b.WriteString(cursorStyle.Render("▶ "))
+		case v.collapsed[i]:
+			b.WriteString(mutedStyle.Render("▸ "))
+		default:
+			b.WriteString("  ")
+		}
+
+		if v.scalar[i] {
+			b.WriteString(v.lines[i])
+		} else {
+			b.WriteString(highlightYAMLLine(v.lines[i], theme))
+		}
+		if v.collapsed[i] {
+			b.WriteString(mutedStyle.Render(" … " + lineCount(v.end[i]-i-1)))
+		}
+		b.WriteString("\n")
+	}
+
+	if start, end, ok := v.statusSection(); ok && !v.showStatus {
+		b.WriteString(mutedStyle.Render("  status: … "+lineCount(end-start-1)+" hidden") + "\n")
+	}
+	return b.String(), cursorRow
+}
+
+// highlightYAMLLine colors a line's keys, list markers, comments and
+// non-string scalars
+func highlightYAMLLine(line string, theme Theme) string {
+	keyStyle := lipgloss.NewStyle().Foreground(theme.Accent)
+	mutedStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	scalarStyle := lipgloss.NewStyle().Foreground(theme.Warning)
+
+	indent := yamlIndent(line)
+	rest := line[indent:]
+	if strings.HasPrefix(rest, "#") {
+		return line[:indent] + mutedStyle.Render(rest)
+	}
+
+	var b strings.Builder
+	b.WriteString(line[:indent])
+	for rest == "-" || strings.HasPrefix(rest, "- ") {
+		b.WriteString(mutedStyle.Render("-"))
+		rest = rest[1:]
+		trimmed := strings.TrimLeft(rest, " ")
+		b.WriteString(rest[:len(rest)-len(trimmed)])
+		rest = trimmed
+	}
+
+	key, value, ok := splitYAMLLine(rest)
+	if ok {
+		b.WriteString(keyStyle.Render(key) + ":")
+		if value == "" {
+			return b.String()
+		}
+		b.WriteString(" ")
+	}
+
+	switch {
+	case isBlockIndicator(value), value == "{}", value == "[]":
+		b.WriteString(mutedStyle.Render(value))
+	case value == "null", value == "~", value == "true", value == "false":
+		b.WriteString(scalarStyle.Render(value))
+	default:
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			b.WriteString(scalarStyle.Render(value))
+		} else {
+			b.WriteString(value)
+		}
+	}
+	return b.String()
+}
+
+// splitYAMLLine splits a line, without its indent and list markers, into
+// key and value. ok is false when the line is a bare value.
+func splitYAMLLine(text string) (key, value string, ok bool) {
+	text = strings.TrimLeft(text, " ")
+	for text == "-" || strings.HasPrefix(text, "- ") {
+		text = strings.TrimLeft(text[1:], " ")
+	}
+
+	// Quoted keys may contain ": "
+	start := 0
+	if strings.HasPrefix(text, `"`) {
+		if quoted, err := strconv.QuotedPrefix(text); err == nil {
+			start = len(quoted)
+		}
+	} else if strings.HasPrefix(text, "'") {
+		if end := strings.Index(text[1:], "'"); end >= 0 {
+			start = end + 2
+		}
+	}
+	if start == 0 && (strings.HasPrefix(text, `"`) || strings.HasPrefix(text, "'")) {
+		return "", text, false
+	}
+
+	if strings.HasSuffix(text, ":") && !strings.Contains(text[start:len(text)-1], ": ") {
+		return text[:len(text)-1], "", true
+	}
+	if i := strings.Index(text[start:], ": "); i >= 0 {
+		return text[:start+i], text[start+i+2:], true
+	}
+	return "", text, false
+}
+
+// lineCount formats a number of lines, such as "1 line" or "12 lines"
+func lineCount(n int) string {
+	if n == 1 {
+		return "1 line"
+	}
+	return fmt.Sprintf("%d lines", n)
+}
+
+// yamlIndent counts a line's leading spaces
+func yamlIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// isYAMLItem reports whether a line starts a list item
+func isYAMLItem(line string) bool {
+	rest := strings.TrimLeft(line, " ")
+	return rest == "-" || strings.HasPrefix(rest, "- ")
+}
+
+// isBlockIndicator reports whether a value starts a multi-line string,
+// such as "|" or ">-"
+func isBlockIndicator(value string) bool {
+	if value == "" || (value[0] != '|' && value[0] != '>') {
+		return false
+	}
+	return strings.Trim(value[1:], "+-0123456789") == ""
+}